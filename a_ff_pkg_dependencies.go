@@ -0,0 +1,44 @@
+package filefactory
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"github.com/outo/filefactory/file"
+)
+
+var impl Implementation
+
+func init() {
+	ResetImplementation()
+}
+
+func GetProductionImplementation() Implementation {
+	return Implementation{
+		//built-in
+		OsLstat:       os.Lstat,
+		IoutilReadDir: ioutil.ReadDir,
+		FilepathWalk:  filepath.Walk,
+		//custom
+		Disk: file.OsDisk{},
+	}
+}
+
+//not recommended to tweak in production
+func MockForTest(mocking func(modifyThis *Implementation)) {
+	mocking(&impl)
+}
+
+func ResetImplementation() {
+	impl = GetProductionImplementation()
+}
+
+type Implementation struct {
+	//builtin
+	OsLstat       func(name string) (os.FileInfo, error)
+	IoutilReadDir func(dirname string) ([]os.FileInfo, error)
+	FilepathWalk  func(root string, walkFn filepath.WalkFunc) error
+	//custom
+	//modifies the disk, unless CreateOptions.Disk is given
+	Disk file.Disk
+}
@@ -12,7 +12,11 @@ func init() {
 
 func GetProductionImplementation() Implementation {
 	i := Implementation{
-		UserCurrentIds: user.CurrentIds,
+		UserCurrentIds:        user.CurrentIds,
+		UserLookupUid:         user.LookupUid,
+		UserLookupGid:         user.LookupGid,
+		UserSupplementaryGids: user.SupplementaryGids,
+		UserNonMemberGid:      user.NonMemberGid,
 	}
 	return i
 }
@@ -20,12 +24,18 @@ func GetProductionImplementation() Implementation {
 //not recommended to tweak in production
 func MockForTest(mocking func(modifyThis *Implementation)) {
 	mocking(&impl)
+	forgetCurrentUserIds()
 }
 
 func ResetImplementation() {
 	impl = GetProductionImplementation()
+	forgetCurrentUserIds()
 }
 
 type Implementation struct {
-	UserCurrentIds func() (uid uint32, primaryGid uint32, otherGid uint32, err error)
+	UserCurrentIds        func() (uid uint32, primaryGid uint32, otherGid uint32, err error)
+	UserLookupUid         func(name string) (uid uint32, err error)
+	UserLookupGid         func(name string) (gid uint32, err error)
+	UserSupplementaryGids func() (gids []uint32, err error)
+	UserNonMemberGid      func() (gid uint32, err error)
 }
@@ -3,44 +3,133 @@
 package attr
 
 import (
-	"time"
+	"errors"
+	"fmt"
 	"os"
+	"sync"
+	"time"
 )
 
+//current user's ids are retrieved lazily, on first use, so that merely importing this package can't fail
 var (
+	currentUserIdsMutex     sync.Mutex
+	currentUserIdsRetrieved bool
 	currentUserUid,
 	currentUserPrimaryGid,
 	currentUserOtherGid uint32
+	currentUserIdsErr error
 )
 
-func CurrentUserUid() uint32        { return currentUserUid }
-func CurrentUserPrimaryGid() uint32 { return currentUserPrimaryGid }
-func CurrentUserOtherGid() uint32   { return currentUserOtherGid }
+func CurrentUserUid() (uint32, error) {
+	uid, _, _, err := currentUserIds()
+	return uid, err
+}
+
+func CurrentUserPrimaryGid() (uint32, error) {
+	_, primaryGid, _, err := currentUserIds()
+	return primaryGid, err
+}
+
+func CurrentUserOtherGid() (uint32, error) {
+	_, _, otherGid, err := currentUserIds()
+	return otherGid, err
+}
+
+//will (re)retrieve current user's ids, normally there is no need to invoke it as it happens on first use
+func RetrieveCurrentUserIds() error {
+	currentUserIdsMutex.Lock()
+	defer currentUserIdsMutex.Unlock()
+	retrieveCurrentUserIds()
+	return currentUserIdsErr
+}
+
+func retrieveCurrentUserIds() {
+	currentUserUid, currentUserPrimaryGid, currentUserOtherGid, currentUserIdsErr = impl.UserCurrentIds()
+	currentUserIdsRetrieved = true
+}
 
-func RetrieveCurrentUserIds() {
-	var err error
-	currentUserUid, currentUserPrimaryGid, currentUserOtherGid, err = impl.UserCurrentIds()
-	if err != nil {
-		panic(err)
+func currentUserIds() (uid, primaryGid, otherGid uint32, err error) {
+	currentUserIdsMutex.Lock()
+	defer currentUserIdsMutex.Unlock()
+	if !currentUserIdsRetrieved {
+		retrieveCurrentUserIds()
 	}
+	return currentUserUid, currentUserPrimaryGid, currentUserOtherGid, currentUserIdsErr
 }
 
-func init() {
-	RetrieveCurrentUserIds()
+//ids will be retrieved again on next use, e.g. once implementation has been changed
+func forgetCurrentUserIds() {
+	currentUserIdsMutex.Lock()
+	defer currentUserIdsMutex.Unlock()
+	currentUserIdsRetrieved = false
 }
 
 //attribute constructors
 func ModePerm(mode os.FileMode) os.FileMode { return mode.Perm() }
 func ArbitraryUid(val uint32) Uid           { return Uid(val) }
 func ArbitraryGid(val uint32) Gid           { return Gid(val) }
-func CurrentUid() Uid                       { return ArbitraryUid(CurrentUserUid()) }
-func PrimaryGid() Gid                       { return ArbitraryGid(CurrentUserPrimaryGid()) }
-func OtherGid() Gid                         { return ArbitraryGid(CurrentUserOtherGid()) }
+
+//current user's ids are resolvers, failure to retrieve them is returned by filefactory.CreateFiles and filefactory.VerifyFiles
+func CurrentUid() Resolver { return currentUserIdResolver("uid", CurrentUserUid, func(id uint32) interface{} { return Uid(id) }) }
+func PrimaryGid() Resolver { return currentUserIdResolver("primary gid", CurrentUserPrimaryGid, func(id uint32) interface{} { return Gid(id) }) }
+func OtherGid() Resolver   { return currentUserIdResolver("other gid", CurrentUserOtherGid, func(id uint32) interface{} { return Gid(id) }) }
+
+func currentUserIdResolver(name string, retrieve func() (uint32, error), toAttribute func(id uint32) interface{}) Resolver {
+	return ResolverFunc(func() (attribute interface{}, err error) {
+		id, err := retrieve()
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to resolve current user's %s: %s", name, err))
+		}
+		return toAttribute(id), nil
+	})
+}
+
+//relative time constraint constructors, they replace exact comparison of the timestamp during verification
+func ModifiedAfter(t time.Time) TimeConstraint        { return TimeConstraint{Kind: ModifiedTimeKind, After: t} }
+func ModifiedBefore(t time.Time) TimeConstraint       { return TimeConstraint{Kind: ModifiedTimeKind, Before: t} }
+func ModifiedWithin(d time.Duration) TimeConstraint   { return TimeConstraint{Kind: ModifiedTimeKind, Within: d} }
+func ModifiedNewerThan(relPath string) TimeConstraint { return TimeConstraint{Kind: ModifiedTimeKind, NewerThan: relPath} }
+func AccessedAfter(t time.Time) TimeConstraint        { return TimeConstraint{Kind: AccessedTimeKind, After: t} }
+func AccessedBefore(t time.Time) TimeConstraint       { return TimeConstraint{Kind: AccessedTimeKind, Before: t} }
+func AccessedWithin(d time.Duration) TimeConstraint   { return TimeConstraint{Kind: AccessedTimeKind, Within: d} }
+func AccessedNewerThan(relPath string) TimeConstraint { return TimeConstraint{Kind: AccessedTimeKind, NewerThan: relPath} }
+func ChangedAfter(t time.Time) TimeConstraint         { return TimeConstraint{Kind: ChangedTimeKind, After: t} }
+func ChangedBefore(t time.Time) TimeConstraint        { return TimeConstraint{Kind: ChangedTimeKind, Before: t} }
+func ChangedWithin(d time.Duration) TimeConstraint    { return TimeConstraint{Kind: ChangedTimeKind, Within: d} }
+func BornAfter(t time.Time) TimeConstraint            { return TimeConstraint{Kind: BirthTimeKind, After: t} }
+func BornBefore(t time.Time) TimeConstraint           { return TimeConstraint{Kind: BirthTimeKind, Before: t} }
 
 //attributes
 type AccessedTime time.Time
 type ModifiedTime time.Time
+
+//change time (ctime) and birth time cannot be set, they are only used for verification
+// e.g. to assert that the file's metadata has not been changed since it was snapshot with file.NewFromPath
+type ChangedTime time.Time
+type BirthTime time.Time
 type Uid uint32
 type Gid uint32
+type TimePrecision time.Duration
 type Size int64
 type Seed int64
+
+//which of the file's timestamps a TimeConstraint applies to
+type TimeKind int
+
+const (
+	ModifiedTimeKind TimeKind = iota
+	AccessedTimeKind
+	ChangedTimeKind
+	BirthTimeKind
+)
+
+//Only one of the fields is expected to be set, use constructors (e.g. ModifiedAfter) rather than a literal.
+//Timestamp will need to be after/before given time, within given duration of the time of verification
+// or newer than the same timestamp of another file (path relative to the same root).
+type TimeConstraint struct {
+	Kind      TimeKind
+	After     time.Time
+	Before    time.Time
+	Within    time.Duration
+	NewerThan string
+}
@@ -38,32 +38,49 @@ var _ = Describe("pkg attr file attribute test", func() {
 					return 15, 25, 35, nil
 				}
 			})
-
-			/*
-			It will normally be populated on package initialisation.
-			In this test it had to be triggered explicitly as - in normal circumstances - the call happens before this test is run,
-			so I couldn't mock it.
-			 */
-			attr.RetrieveCurrentUserIds()
+		})
+		AfterEach(func() {
+			attr.ResetImplementation()
 		})
 
 		Specify("that file's owner needs to be set to current user's id. ", func() {
-			actual := attr.CurrentUid()
+			actual, err := attr.CurrentUid().Resolve()
+			Expect(err).ShouldNot(HaveOccurred())
 			Expect(actual).To(BeAssignableToTypeOf(attr.Uid(0)))
 			Expect(actual).To(BeEquivalentTo(15))
 		})
 
 		Specify("that file's group needs to be set to current user's primary group id. ", func() {
-			actual := attr.PrimaryGid()
+			actual, err := attr.PrimaryGid().Resolve()
+			Expect(err).ShouldNot(HaveOccurred())
 			Expect(actual).To(BeAssignableToTypeOf(attr.Gid(0)))
 			Expect(actual).To(BeEquivalentTo(25))
 		})
 
 		Specify("that file's group needs to be set to current user's group id, other than primary group id (if available). ", func() {
-			actual := attr.OtherGid()
+			actual, err := attr.OtherGid().Resolve()
+			Expect(err).ShouldNot(HaveOccurred())
 			Expect(actual).To(BeAssignableToTypeOf(attr.Gid(0)))
 			Expect(actual).To(BeEquivalentTo(35))
 		})
+
+		It("will retrieve current user ids lazily and only once", func() {
+			invocations := 0
+			attr.MockForTest(func(modifyThis *attr.Implementation) {
+				modifyThis.UserCurrentIds = func() (uid uint32, primaryGid uint32, otherGid uint32, err error) {
+					invocations++
+					return 15, 25, 35, nil
+				}
+			})
+			Expect(invocations).To(BeZero())
+			attr.CurrentUid().Resolve()
+			attr.PrimaryGid().Resolve()
+			attr.OtherGid().Resolve()
+			Expect(invocations).To(Equal(1))
+
+			Expect(attr.RetrieveCurrentUserIds()).ShouldNot(HaveOccurred())
+			Expect(invocations).To(Equal(2))
+		})
 	})
 
 	Describe("given failing user.CurrentIds() invocation", func() {
@@ -75,9 +92,22 @@ var _ = Describe("pkg attr file attribute test", func() {
 				}
 			})
 		})
+		AfterEach(func() {
+			attr.ResetImplementation()
+		})
+
+		It("will not panic, but return the error", func() {
+			Expect(func() { attr.RetrieveCurrentUserIds() }).NotTo(Panic())
+			Expect(attr.RetrieveCurrentUserIds()).To(MatchError("user.CurrentIds() error"))
+		})
 
-		It("will panic", func() {
-			Expect(attr.RetrieveCurrentUserIds).To(Panic())
+		It("will return the error upon resolution of current user's ids", func() {
+			_, err := attr.CurrentUid().Resolve()
+			Expect(err).To(MatchError("unable to resolve current user's uid: user.CurrentIds() error"))
+			_, err = attr.PrimaryGid().Resolve()
+			Expect(err).To(MatchError("unable to resolve current user's primary gid: user.CurrentIds() error"))
+			_, err = attr.OtherGid().Resolve()
+			Expect(err).To(MatchError("unable to resolve current user's other gid: user.CurrentIds() error"))
 		})
 	})
 
@@ -95,6 +125,23 @@ var _ = Describe("pkg attr file attribute test", func() {
 		Expect(actual).To(BeEquivalentTo(now))
 	})
 
+	Specify("relative constraints of file's modified and accessed time", func() {
+		now := time.Now()
+		Expect(attr.ModifiedAfter(now)).To(Equal(attr.TimeConstraint{Kind: attr.ModifiedTimeKind, After: now}))
+		Expect(attr.ModifiedBefore(now)).To(Equal(attr.TimeConstraint{Kind: attr.ModifiedTimeKind, Before: now}))
+		Expect(attr.ModifiedWithin(time.Minute)).To(Equal(attr.TimeConstraint{Kind: attr.ModifiedTimeKind, Within: time.Minute}))
+		Expect(attr.ModifiedNewerThan("other")).To(Equal(attr.TimeConstraint{Kind: attr.ModifiedTimeKind, NewerThan: "other"}))
+		Expect(attr.AccessedAfter(now)).To(Equal(attr.TimeConstraint{Kind: attr.AccessedTimeKind, After: now}))
+		Expect(attr.AccessedBefore(now)).To(Equal(attr.TimeConstraint{Kind: attr.AccessedTimeKind, Before: now}))
+		Expect(attr.AccessedWithin(time.Minute)).To(Equal(attr.TimeConstraint{Kind: attr.AccessedTimeKind, Within: time.Minute}))
+		Expect(attr.AccessedNewerThan("other")).To(Equal(attr.TimeConstraint{Kind: attr.AccessedTimeKind, NewerThan: "other"}))
+		Expect(attr.ChangedAfter(now)).To(Equal(attr.TimeConstraint{Kind: attr.ChangedTimeKind, After: now}))
+		Expect(attr.ChangedBefore(now)).To(Equal(attr.TimeConstraint{Kind: attr.ChangedTimeKind, Before: now}))
+		Expect(attr.ChangedWithin(time.Minute)).To(Equal(attr.TimeConstraint{Kind: attr.ChangedTimeKind, Within: time.Minute}))
+		Expect(attr.BornAfter(now)).To(Equal(attr.TimeConstraint{Kind: attr.BirthTimeKind, After: now}))
+		Expect(attr.BornBefore(now)).To(Equal(attr.TimeConstraint{Kind: attr.BirthTimeKind, Before: now}))
+	})
+
 	Specify("number of bytes that this file will consist of", func() {
 		actual := attr.Size(1709)
 		Expect(actual).To(BeAssignableToTypeOf(attr.Size(0)))
@@ -107,7 +154,4 @@ var _ = Describe("pkg attr file attribute test", func() {
 		Expect(actual).To(BeEquivalentTo(82736))
 	})
 
-	It("will retrieve current user ids on production package initialisation, but I am unable to test this invocation", func() {
-
-	})
 })
@@ -0,0 +1,55 @@
+package attr
+
+import "fmt"
+
+//ContentEdit is an attribute of a regular file changing the Size bytes generated with Seed.
+//Edits are applied in the order given, both to create the file and to tell what its contents are expected to be,
+// so that a definition can describe e.g. a log after something was appended to it.
+//Like writing to a file, offsets past the end (and Truncate to a bigger size) fill the gap with zero bytes.
+type ContentEdit interface {
+	fmt.Stringer
+	contentEdit()
+}
+
+//bytes added at the end
+type Append []byte
+
+//bytes inserted at offset, bytes from offset on are moved after them
+type Insert struct {
+	Offset int64
+	Bytes  []byte
+}
+
+//bytes replaced from offset on, contents grow if they go past the end
+type Overwrite struct {
+	Offset int64
+	Bytes  []byte
+}
+
+//contents cut (or extended) to the size
+type Truncate int64
+
+//Size bytes generated with Seed (by the generator of the file), added at the end
+type Segment struct {
+	Size int64
+	Seed int64
+}
+
+//content edit constructors
+func AppendString(s string) Append                  { return Append(s) }
+func InsertAt(offset int64, bs []byte) Insert       { return Insert{Offset: offset, Bytes: bs} }
+func OverwriteAt(offset int64, bs []byte) Overwrite { return Overwrite{Offset: offset, Bytes: bs} }
+func TruncateTo(size int64) Truncate                { return Truncate(size) }
+func Concat(size, seed int64) Segment               { return Segment{Size: size, Seed: seed} }
+
+func (Append) contentEdit()    {}
+func (Insert) contentEdit()    {}
+func (Overwrite) contentEdit() {}
+func (Truncate) contentEdit()  {}
+func (Segment) contentEdit()   {}
+
+func (e Append) String() string    { return fmt.Sprintf("append %d bytes", len(e)) }
+func (e Insert) String() string    { return fmt.Sprintf("insert %d bytes at %d", len(e.Bytes), e.Offset) }
+func (e Overwrite) String() string { return fmt.Sprintf("overwrite %d bytes at %d", len(e.Bytes), e.Offset) }
+func (e Truncate) String() string  { return fmt.Sprintf("truncate to %d", int64(e)) }
+func (e Segment) String() string   { return fmt.Sprintf("concat %d bytes of seed %d", e.Size, e.Seed) }
@@ -0,0 +1,39 @@
+package attr_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/outo/filefactory/attr"
+)
+
+var _ = Describe("pkg attr content_edit.go unit test", func() {
+
+	It("will construct content edits", func() {
+		Expect(attr.AppendString("tail")).To(Equal(attr.Append("tail")))
+		Expect(attr.InsertAt(3, []byte("in"))).To(Equal(attr.Insert{Offset: 3, Bytes: []byte("in")}))
+		Expect(attr.OverwriteAt(4, []byte("over"))).To(Equal(attr.Overwrite{Offset: 4, Bytes: []byte("over")}))
+		Expect(attr.TruncateTo(10)).To(Equal(attr.Truncate(10)))
+		Expect(attr.Concat(20, 7)).To(Equal(attr.Segment{Size: 20, Seed: 7}))
+	})
+
+	It("will describe content edits in a human readable way", func() {
+		edits := []attr.ContentEdit{
+			attr.AppendString("tail"),
+			attr.InsertAt(3, []byte("in")),
+			attr.OverwriteAt(4, []byte("over")),
+			attr.TruncateTo(10),
+			attr.Concat(20, 7),
+		}
+		var descriptions []string
+		for _, edit := range edits {
+			descriptions = append(descriptions, edit.String())
+		}
+		Expect(descriptions).To(Equal([]string{
+			"append 4 bytes",
+			"insert 2 bytes at 3",
+			"overwrite 4 bytes at 4",
+			"truncate to 10",
+			"concat 20 bytes of seed 7",
+		}))
+	})
+})
@@ -0,0 +1,65 @@
+package attr
+
+import (
+	"errors"
+	"fmt"
+)
+
+//Resolver is an attribute which value is only known once resolved, e.g. uid of a user with given name.
+//It is resolved when definition is constructed, resolution error will be returned by filefactory.CreateFiles
+// and filefactory.VerifyFiles rather than cause a panic.
+type Resolver interface {
+	Resolve() (attribute interface{}, err error)
+}
+
+//adapter allowing use of ordinary function as a Resolver
+type ResolverFunc func() (attribute interface{}, err error)
+
+func (f ResolverFunc) Resolve() (attribute interface{}, err error) { return f() }
+
+//owner (uid) of the user with given name, looked up in the user database
+func OwnerName(name string) Resolver {
+	return ResolverFunc(func() (attribute interface{}, err error) {
+		uid, err := impl.UserLookupUid(name)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to resolve owner name %q: %s", name, err))
+		}
+		return Uid(uid), nil
+	})
+}
+
+//group (gid) with given name, looked up in the user database
+func GroupName(name string) Resolver {
+	return ResolverFunc(func() (attribute interface{}, err error) {
+		gid, err := impl.UserLookupGid(name)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to resolve group name %q: %s", name, err))
+		}
+		return Gid(gid), nil
+	})
+}
+
+//n-th (zero based) of the current user's groups other than primary group
+func SupplementaryGid(n int) Resolver {
+	return ResolverFunc(func() (attribute interface{}, err error) {
+		gids, err := impl.UserSupplementaryGids()
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to resolve supplementary group #%d: %s", n, err))
+		}
+		if n < 0 || n >= len(gids) {
+			return nil, errors.New(fmt.Sprintf("unable to resolve supplementary group #%d, current user has %d supplementary group(s)", n, len(gids)))
+		}
+		return Gid(gids[n]), nil
+	})
+}
+
+//a group the current user is not a member of, for negative tests
+func NonMemberGid() Resolver {
+	return ResolverFunc(func() (attribute interface{}, err error) {
+		gid, err := impl.UserNonMemberGid()
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to resolve group the current user is not a member of: %s", err))
+		}
+		return Gid(gid), nil
+	})
+}
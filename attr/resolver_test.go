@@ -0,0 +1,91 @@
+package attr_test
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/outo/filefactory/attr"
+)
+
+var _ = Describe("pkg attr resolver.go unit test", func() {
+
+	BeforeEach(func() {
+		attr.MockForTest(func(modifyThis *attr.Implementation) {
+			modifyThis.UserLookupUid = func(name string) (uint32, error) {
+				if name == "nobody" {
+					return 65534, nil
+				}
+				return 0, errors.New("unknown user " + name)
+			}
+			modifyThis.UserLookupGid = func(name string) (uint32, error) {
+				if name == "adm" {
+					return 4, nil
+				}
+				return 0, errors.New("unknown group " + name)
+			}
+			modifyThis.UserSupplementaryGids = func() ([]uint32, error) {
+				return []uint32{27, 4}, nil
+			}
+			modifyThis.UserNonMemberGid = func() (uint32, error) {
+				return 65534, nil
+			}
+		})
+	})
+
+	AfterEach(func() {
+		attr.ResetImplementation()
+	})
+
+	It("will adapt ordinary function to Resolver", func() {
+		resolved, err := attr.ResolverFunc(func() (interface{}, error) { return attr.Size(5), nil }).Resolve()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved).To(Equal(attr.Size(5)))
+	})
+
+	It("will resolve owner name to Uid", func() {
+		resolved, err := attr.OwnerName("nobody").Resolve()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved).To(Equal(attr.Uid(65534)))
+	})
+
+	It("will return error if owner name can't be resolved", func() {
+		_, err := attr.OwnerName("ghost").Resolve()
+		Expect(err).To(MatchError(`unable to resolve owner name "ghost": unknown user ghost`))
+	})
+
+	It("will resolve group name to Gid", func() {
+		resolved, err := attr.GroupName("adm").Resolve()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved).To(Equal(attr.Gid(4)))
+	})
+
+	It("will return error if group name can't be resolved", func() {
+		_, err := attr.GroupName("ghosts").Resolve()
+		Expect(err).To(MatchError(`unable to resolve group name "ghosts": unknown group ghosts`))
+	})
+
+	It("will resolve n-th supplementary group to Gid", func() {
+		resolved, err := attr.SupplementaryGid(1).Resolve()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved).To(Equal(attr.Gid(4)))
+	})
+
+	It("will return error if there is no n-th supplementary group", func() {
+		_, err := attr.SupplementaryGid(2).Resolve()
+		Expect(err).To(MatchError("unable to resolve supplementary group #2, current user has 2 supplementary group(s)"))
+	})
+
+	It("will resolve group the current user is not a member of to Gid", func() {
+		resolved, err := attr.NonMemberGid().Resolve()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolved).To(Equal(attr.Gid(65534)))
+	})
+
+	It("will return error if there is no group the current user is not a member of", func() {
+		attr.MockForTest(func(modifyThis *attr.Implementation) {
+			modifyThis.UserNonMemberGid = func() (uint32, error) { return 0, errors.New("none found") }
+		})
+		_, err := attr.NonMemberGid().Resolve()
+		Expect(err).To(MatchError("unable to resolve group the current user is not a member of: none found"))
+	})
+})
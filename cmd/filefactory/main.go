@@ -0,0 +1,275 @@
+//Command filefactory creates, verifies and snapshots file trees described by manifests (see package manifest),
+// for shell based tests and scripts.
+//
+//	filefactory create -manifest tree.json -root /tmp/fixture
+//	filefactory create -manifest tree.json -root /tmp/fixture -plan -format json
+//	filefactory verify -manifest tree.json -root /tmp/fixture -format tree -contents=false
+//	filefactory snapshot -root /tmp/fixture -output tree.json
+//
+//Exit code is 0 on success (and for -h of any command), 1 if verification found differences and 2 on any other error.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/manifest"
+	"github.com/outo/filefactory/plan"
+	"github.com/outo/filefactory/verify"
+)
+
+const (
+	ExitOk          = 0
+	ExitDifferences = 1
+	ExitFailure     = 2
+)
+
+const usage = `usage: filefactory <command> [flags]
+
+commands:
+  create    creates files of the manifest under root
+  verify    verifies files under root against the manifest, reports differences
+  snapshot  writes manifest describing files under root
+
+run filefactory <command> -h for the command's flags
+`
+
+//verification instructions available as flags, named after their aspects
+var instructionSwitches = []func(verify bool) verify.Instruction{
+	verify.AllByDefault,
+	verify.ModePerm,
+	verify.Uid,
+	verify.Gid,
+	verify.ModifiedTime,
+	verify.AccessedTime,
+	verify.ChangedTime,
+	verify.BirthTime,
+	verify.Size,
+	verify.SymlinkTarget,
+	verify.Contents,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return ExitFailure
+	}
+
+	var err error
+	switch args[0] {
+	case "create":
+		err = create(args[1:], stdout, stderr)
+	case "verify":
+		err = verifyTree(args[1:], stdout, stderr)
+	case "snapshot":
+		err = snapshot(args[1:], stdout, stderr)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, usage)
+		return ExitOk
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return ExitFailure
+	}
+
+	switch err.(type) {
+	case nil:
+		return ExitOk
+	case *verify.Errors:
+		return ExitDifferences
+	default:
+		if err == flag.ErrHelp {
+			//the flags have been printed, as asked for with -h
+			return ExitOk
+		}
+		fmt.Fprintf(stderr, "filefactory %s: %s\n", args[0], err)
+		return ExitFailure
+	}
+}
+
+func newFlagSet(command string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+//registers a flag per verification instruction, returned function provides instructions of the flags which were set
+func instructionFlags(flags *flag.FlagSet) func() []interface{} {
+	values := map[string]*bool{}
+	for _, instruction := range instructionSwitches {
+		aspect := instruction(true).Aspect
+		values[aspect] = flags.Bool(aspect, true, fmt.Sprintf("verify %s", aspect))
+	}
+	return func() (instructions []interface{}) {
+		//only the ones explicitly set, "all" first so that the others can override it
+		flags.Visit(func(f *flag.Flag) {
+			if f.Name == verify.AllByDefault(true).Aspect {
+				instructions = append([]interface{}{verify.AllByDefault(*values[f.Name])}, instructions...)
+			} else if value, ok := values[f.Name]; ok {
+				instructions = append(instructions, verify.NewInstruction(*value, f.Name))
+			}
+		})
+		return
+	}
+}
+
+func requireFlag(name, value string) error {
+	if value == "" {
+		return errors.New(fmt.Sprintf("-%s is required", name))
+	}
+	return nil
+}
+
+//definitions of the manifest, constructed by a factory with given defaults
+func load(manifestPath string, factoryDefaults ...interface{}) (files []file.File, err error) {
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		return
+	}
+	constructors, err := m.Constructors()
+	if err != nil {
+		return
+	}
+	return filefactory.New(factoryDefaults...).FilesToCreate(constructors...), nil
+}
+
+func create(args []string, stdout, stderr io.Writer) (err error) {
+	flags := newFlagSet("create", stderr)
+	manifestPath := flags.String("manifest", "", "manifest to create files of (required)")
+	root := flags.String("root", "", "directory to create files under (required)")
+	dryRun := flags.Bool("plan", false, "print operations which would be performed instead of creating files")
+	format := flags.String("format", "text", "plan format: text or json")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if *format != "text" && *format != "json" {
+		return errors.New(fmt.Sprintf("unknown format %q", *format))
+	}
+	if err = requireFlag("manifest", *manifestPath); err != nil {
+		return
+	}
+	if err = requireFlag("root", *root); err != nil {
+		return
+	}
+
+	files, err := load(*manifestPath)
+	if err != nil {
+		return
+	}
+	if !*dryRun {
+		return filefactory.CreateFiles(*root, files...)
+	}
+
+	p, err := plan.CreateFiles(*root, files...)
+	if err != nil {
+		return
+	}
+	var output []byte
+	if *format == "json" {
+		if output, err = json.MarshalIndent(p, "", "  "); err != nil {
+			return
+		}
+	} else {
+		output = []byte(p.String())
+	}
+	_, err = fmt.Fprintln(stdout, string(output))
+	return
+}
+
+func verifyTree(args []string, stdout, stderr io.Writer) (err error) {
+	flags := newFlagSet("verify", stderr)
+	manifestPath := flags.String("manifest", "", "manifest to verify files against (required)")
+	root := flags.String("root", "", "directory with files to verify (required)")
+	format := flags.String("format", "text", "report format: text, tree, json or junit")
+	colour := flags.Bool("colour", false, "highlight tree format with ANSI colour")
+	instructions := instructionFlags(flags)
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if err = requireFlag("manifest", *manifestPath); err != nil {
+		return
+	}
+	if err = requireFlag("root", *root); err != nil {
+		return
+	}
+	switch *format {
+	case "text", "tree", "json", "junit":
+	default:
+		return errors.New(fmt.Sprintf("unknown format %q", *format))
+	}
+
+	files, err := load(*manifestPath, instructions()...)
+	if err != nil {
+		return
+	}
+
+	err = filefactory.VerifyFiles(*root, files...)
+	verr, ok := err.(*verify.Errors)
+	if !ok {
+		return
+	}
+
+	//reports of any format end with a single newline, added when printed (as with the plan of create)
+	var (
+		report    []byte
+		reportErr error
+	)
+	switch *format {
+	case "text":
+		report = []byte(strings.TrimSuffix(verr.Error(), "\n"))
+	case "tree":
+		report = []byte(strings.TrimSuffix(verr.Tree(*colour), "\n"))
+	case "json":
+		report, reportErr = json.MarshalIndent(verr, "", "  ")
+	case "junit":
+		report, reportErr = verr.JUnit(*manifestPath)
+	}
+	if reportErr != nil {
+		return reportErr
+	}
+	if _, err = fmt.Fprintln(stdout, string(report)); err != nil {
+		return
+	}
+	return verr
+}
+
+func snapshot(args []string, stdout, stderr io.Writer) (err error) {
+	flags := newFlagSet("snapshot", stderr)
+	root := flags.String("root", "", "directory to snapshot (required)")
+	output := flags.String("output", "", "file to write the manifest to (default standard output)")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if err = requireFlag("root", *root); err != nil {
+		return
+	}
+
+	m, err := manifest.Snapshot(*root)
+	if err != nil {
+		return
+	}
+
+	//make sure the manifest describes the tree, e.g. it has no entries of unsupported types
+	constructors, err := m.Constructors()
+	if err != nil {
+		return
+	}
+	err = filefactory.VerifyFiles(*root, filefactory.New().FilesToExpect(constructors...)...)
+	if err != nil {
+		return errors.New(fmt.Sprintf("snapshot does not describe %s: %s", *root, err))
+	}
+
+	if *output == "" {
+		return m.Write(stdout)
+	}
+	return m.Save(*output)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("filefactory command", func() {
+
+	const document = `{
+  "files": [
+    {"type": "dir", "path": "dir", "mode": "0750"},
+    {"type": "reg", "path": "dir/file", "mode": "0640", "size": 5, "seed": 7},
+    {"type": "sym", "path": "link", "target": "dir/file"}
+  ]
+}`
+
+	var (
+		tempDir,
+		manifestPath,
+		root string
+		stdout,
+		stderr *bytes.Buffer
+	)
+
+	invoke := func(args ...string) int {
+		return run(args, stdout, stderr)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "filefactory-command-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+		manifestPath = filepath.Join(tempDir, "manifest.json")
+		Expect(ioutil.WriteFile(manifestPath, []byte(document), 0600)).To(Succeed())
+		root = filepath.Join(tempDir, "root")
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("will print usage and fail without command", func() {
+		Expect(invoke()).To(Equal(ExitFailure))
+		Expect(stderr.String()).To(ContainSubstring("usage: filefactory <command>"))
+	})
+
+	It("will print flags of the command and succeed with -h", func() {
+		for _, command := range []string{"create", "verify", "snapshot"} {
+			stderr.Reset()
+			Expect(invoke(command, "-h")).To(Equal(ExitOk))
+			Expect(stderr.String()).To(ContainSubstring("-root"))
+			Expect(stderr.String()).ToNot(ContainSubstring("filefactory " + command + ":"))
+		}
+	})
+
+	It("will fail on unknown command", func() {
+		Expect(invoke("destroy")).To(Equal(ExitFailure))
+		Expect(stderr.String()).To(ContainSubstring(`unknown command "destroy"`))
+	})
+
+	It("will fail if required flag is missing", func() {
+		Expect(invoke("create", "-root", root)).To(Equal(ExitFailure))
+		Expect(stderr.String()).To(ContainSubstring("-manifest is required"))
+	})
+
+	It("will print the plan instead of creating files", func() {
+		Expect(invoke("create", "-manifest", manifestPath, "-root", root, "-plan")).To(Equal(ExitOk), stderr.String())
+		Expect(stdout.String()).To(ContainSubstring("write 5 bytes 0640 " + filepath.Join(root, "dir/file")))
+		Expect(root).NotTo(BeAnExistingFile())
+
+		stdout.Reset()
+		Expect(invoke("create", "-manifest", manifestPath, "-root", root, "-plan", "-format", "json")).To(Equal(ExitOk), stderr.String())
+		var operations []map[string]interface{}
+		Expect(json.Unmarshal(stdout.Bytes(), &operations)).To(Succeed())
+		Expect(operations).To(ContainElement(HaveKeyWithValue("op", "symlink")))
+		Expect(root).NotTo(BeAnExistingFile())
+	})
+
+	It("will create files of the manifest, which then verify", func() {
+		Expect(invoke("create", "-manifest", manifestPath, "-root", root)).To(Equal(ExitOk), stderr.String())
+		info, err := os.Stat(filepath.Join(root, "dir/file"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(info.Size()).To(Equal(int64(5)))
+
+		Expect(invoke("verify", "-manifest", manifestPath, "-root", root)).To(Equal(ExitOk), stderr.String())
+		Expect(stdout.String()).To(BeEmpty())
+	})
+
+	Describe("given files differ from the manifest", func() {
+		BeforeEach(func() {
+			Expect(invoke("create", "-manifest", manifestPath, "-root", root)).To(Equal(ExitOk), stderr.String())
+			Expect(os.Chmod(filepath.Join(root, "dir/file"), 0600)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(root, "dir/file"), []byte("12345"), 0600)).To(Succeed())
+		})
+
+		It("will exit with differences and report them", func() {
+			Expect(invoke("verify", "-manifest", manifestPath, "-root", root)).To(Equal(ExitDifferences))
+			Expect(stdout.String()).To(ContainSubstring(filepath.Join(root, "dir/file") + ": expected -rw-r-----, actual -rw-------"))
+		})
+
+		It("will not report differences of the aspects switched off with flags", func() {
+			Expect(invoke("verify", "-manifest", manifestPath, "-root", root, "-mode-perm=false", "-contents=false")).To(Equal(ExitOk), stdout.String())
+			Expect(invoke("verify", "-manifest", manifestPath, "-root", root, "-all=false", "-contents")).To(Equal(ExitDifferences))
+			Expect(stdout.String()).ToNot(ContainSubstring("expected -rw-r-----"))
+		})
+
+		It("will report as tree", func() {
+			Expect(invoke("verify", "-manifest", manifestPath, "-root", root, "-format", "tree")).To(Equal(ExitDifferences))
+			Expect(stdout.String()).To(HavePrefix("dir/\n  file [P C]\n"))
+		})
+
+		It("will report as JSON", func() {
+			Expect(invoke("verify", "-manifest", manifestPath, "-root", root, "-format", "json")).To(Equal(ExitDifferences))
+			report := map[string]interface{}{}
+			Expect(json.Unmarshal(stdout.Bytes(), &report)).To(Succeed())
+			Expect(report["difference"]).To(Equal("ModePerm|Contents"))
+		})
+
+		It("will report as JUnit XML", func() {
+			Expect(invoke("verify", "-manifest", manifestPath, "-root", root, "-format", "junit")).To(Equal(ExitDifferences))
+			Expect(stdout.String()).To(ContainSubstring(`<testsuite name="` + manifestPath + `" tests="1" failures="1">`))
+		})
+
+		It("will end report of any format with a single newline", func() {
+			for _, format := range []string{"text", "tree", "json", "junit"} {
+				stdout.Reset()
+				Expect(invoke("verify", "-manifest", manifestPath, "-root", root, "-format", format)).To(Equal(ExitDifferences))
+				Expect(stdout.String()).To(HaveSuffix("\n"), format)
+				Expect(stdout.String()).ToNot(HaveSuffix("\n\n"), format)
+			}
+		})
+
+		It("will fail on unknown format", func() {
+			Expect(invoke("verify", "-manifest", manifestPath, "-root", root, "-format", "yaml")).To(Equal(ExitFailure))
+		})
+	})
+
+	It("will snapshot a tree into manifest which it verifies against", func() {
+		Expect(invoke("create", "-manifest", manifestPath, "-root", root)).To(Equal(ExitOk), stderr.String())
+
+		snapshotPath := filepath.Join(tempDir, "snapshot.json")
+		Expect(invoke("snapshot", "-root", root, "-output", snapshotPath)).To(Equal(ExitOk), stderr.String())
+		Expect(invoke("verify", "-manifest", snapshotPath, "-root", root)).To(Equal(ExitOk), stdout.String())
+
+		Expect(invoke("snapshot", "-root", root)).To(Equal(ExitOk), stderr.String())
+		Expect(stdout.String()).To(ContainSubstring(`"path": "dir/file"`))
+	})
+})
@@ -0,0 +1,334 @@
+package filefactory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"github.com/outo/filefactory/file"
+)
+
+//options of CreateFilesWith, the zero value behaves as CreateFiles
+type CreateOptions struct {
+	//If creation fails, the paths this call created (parents and root included) are removed.
+	//Files which existed before stay, but are not restored: a rewritten regular file keeps its new contents and
+	// aligned attributes (owner, mode, times) keep their new values. Files removed by the Policy are not restored either.
+	Rollback bool
+	//The tree is built in a sibling staging directory and moved into place once complete, so that root is either
+	// fully populated or not there at all. Root must not exist or be an empty directory, which the tree replaces taking
+	// over its mode and owner. Missing parents of root are created, and removed if creation fails.
+	//On Linux an existing root is swapped atomically (renameat2 RENAME_EXCHANGE); where that is not supported, it is
+	// removed right before the rename, so for a moment there is no root.
+	Staging bool
+	//what happens to paths which already exist (files removed by the policy can't be rolled back)
+	Policy CreationPolicy
+	//Definitions create regular files and directories with the mode defined, regardless of the process umask.
+	//If set, permission bits set here are cleared from that mode right after creation, e.g. 027 gives what umask 027
+	// would give. Unless the mode alignment is skipped, the defined mode is set again when aligning.
+	//It does not apply to parents which are not defined, they are made with MkdirAll(dir, 0777) and get the process
+	// umask (see ImplicitParents to define them).
+	Umask os.FileMode
+	//alignment of these attributes is skipped, the zero value aligns all of them (custom attributes are always aligned)
+	SkipAlign AlignPhases
+	//if set, paths of definitions which did not exist before are added to it, see RemoveFilesWith
+	Created *Created
+	//Every modification of the disk goes through it (e.g. pkg plan passes one recording the operations), if not set
+	// file.OsDisk is used. The definitions have to implement file.DiskCreator and file.DiskAttributesAligner for it,
+	// as all of pkg def do, otherwise creation fails.
+	Disk file.Disk
+}
+
+//attributes aligned once all files are created, combine with |
+type AlignPhases uint
+
+const (
+	AlignOwnership AlignPhases = 1 << iota
+	AlignMode
+	AlignTimes
+)
+
+//phase of CreateFilesWith a definition failed in
+type Phase string
+
+const (
+	PhaseCreate Phase = "create"
+	PhaseAlign  Phase = "align"
+)
+
+//returned by CreateFiles and CreateFilesWith, tells which definition failed and in which phase
+type CreationError struct {
+	File  file.File
+	Phase Phase
+	Err   error
+	//not nil if Rollback was requested but did not succeed
+	RollbackErr error
+}
+
+func (e *CreationError) Error() string {
+	message := fmt.Sprintf("%s phase failed for %s: %s", e.Phase, e.File.GetPath(), e.Err)
+	if e.RollbackErr != nil {
+		message += fmt.Sprintf(" (rollback failed: %s)", e.RollbackErr)
+	}
+	return message
+}
+
+func (e *CreationError) Unwrap() error {
+	return e.Err
+}
+
+func CreateFilesWith(root string, options CreateOptions, files ...file.File) (err error) {
+	if err = Validate(files...); err != nil {
+		return
+	}
+
+	if options.Staging {
+		return createStaged(root, options, files...)
+	}
+
+	c := newCreation(root, options)
+	if err = c.createAndAlign(files...); err != nil && options.Rollback {
+		err.(*CreationError).RollbackErr = c.rollback()
+	}
+	return
+}
+
+type creation struct {
+	root string
+	//root as given to CreateFilesWith, differs from root when staging
+	target  string
+	options CreateOptions
+	disk    file.Disk
+	//absolute paths which did not exist before this creation, in order of creation
+	created []string
+}
+
+func newCreation(root string, options CreateOptions) *creation {
+	disk := options.Disk
+	if disk == nil {
+		disk = impl.Disk
+	}
+	return &creation{root: root, target: root, options: options, disk: disk}
+}
+
+func (c *creation) createAndAlign(files ...file.File) (err error) {
+	for _, f := range files {
+		var skipCreate bool
+		if skipCreate, err = c.applyPolicy(f); err != nil {
+			return &CreationError{File: f, Phase: PhaseCreate, Err: err}
+		}
+		if skipCreate {
+			continue
+		}
+		if c.options.Rollback {
+			if err = c.recordMissing(f); err != nil {
+				return &CreationError{File: f, Phase: PhaseCreate, Err: err}
+			}
+		}
+		var existed bool
+		if existed, err = c.exists(f); err != nil {
+			return &CreationError{File: f, Phase: PhaseCreate, Err: err}
+		}
+		if err = c.create(f); err != nil {
+			return &CreationError{File: f, Phase: PhaseCreate, Err: err}
+		}
+		if c.options.Created != nil && !existed {
+			c.options.Created.add(filepath.Join(c.target, f.GetPath()))
+		}
+		if err = c.applyUmask(f); err != nil {
+			return &CreationError{File: f, Phase: PhaseCreate, Err: err}
+		}
+	}
+
+	//Split into two loops, so that attributes (especially timestamps) are aligned only once all files are created.
+	//You could have a directory created and attributes aligned, and then you may need to create a file within this directory.
+	//Doing that will update (on NIXes) modified and change timestamps on the directory itself which means the
+	// modified timestamp will be updated with current time.
+	skip := c.options.SkipAlign
+	for _, f := range files {
+		if err = c.align(f, skip&AlignOwnership == 0, skip&AlignMode == 0, skip&AlignTimes == 0); err != nil {
+			return &CreationError{File: f, Phase: PhaseAlign, Err: err}
+		}
+	}
+	return
+}
+
+//definitions which can't be created on a given disk are only accepted if CreateOptions.Disk is not set
+func (c *creation) create(f file.File) error {
+	if creator, ok := f.(file.DiskCreator); ok {
+		return creator.CreateOn(c.disk, c.root)
+	} else if c.options.Disk != nil {
+		return errors.New(fmt.Sprintf("%s can't be created on CreateOptions.Disk, it does not implement file.DiskCreator", f.GetPath()))
+	}
+	return f.Create(c.root)
+}
+
+func (c *creation) align(f file.File, ownership, mode, times bool) error {
+	if aligner, ok := f.(file.DiskAttributesAligner); ok {
+		return aligner.AlignAttributesOn(c.disk, ownership, mode, times, c.root)
+	} else if c.options.Disk != nil {
+		return errors.New(fmt.Sprintf("attributes of %s can't be aligned on CreateOptions.Disk, it does not implement file.DiskAttributesAligner", f.GetPath()))
+	}
+	return f.AlignAttributes(ownership, mode, times, c.root)
+}
+
+func (c *creation) applyUmask(f file.File) (err error) {
+	mode := f.GetMode()
+	if c.options.Umask&os.ModePerm == 0 || !mode.IsRegular() && !mode.IsDir() {
+		return
+	}
+	if _, err = file.ResolveBeneath(c.root, f.GetPath(), true); err != nil {
+		return
+	}
+	return c.disk.Chmod(c.root, f.GetPath(), mode&^(c.options.Umask&os.ModePerm))
+}
+
+//only looked up if CreateOptions.Created is set
+func (c *creation) exists(f file.File) (exists bool, err error) {
+	if c.options.Created == nil {
+		return
+	}
+	path, err := file.ResolveBeneath(c.root, f.GetPath(), false)
+	if err != nil {
+		//nothing will be created, Create is going to refuse it
+		return false, nil
+	}
+	if _, err = impl.OsLstat(path); os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+//records the file and its parents (root included) which do not exist yet, so that they can be rolled back
+func (c *creation) recordMissing(f file.File) (err error) {
+	path, err := file.ResolveBeneath(c.root, f.GetPath(), false)
+	if err != nil {
+		//nothing will be created, Create is going to refuse it
+		return nil
+	}
+
+	missing, err := missingAncestors(path)
+	if err != nil {
+		return
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		c.created = append(c.created, missing[i])
+	}
+	return nil
+}
+
+//removes what was created, most recent first
+func (c *creation) rollback() (err error) {
+	for i := len(c.created) - 1; i >= 0; i-- {
+		path := c.created[i]
+		if _, err = impl.OsLstat(path); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return
+		}
+		if err = withWritableParent(c.disk, path, func() error { return c.disk.Remove(path) }); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+func createStaged(root string, options CreateOptions, files ...file.File) (err error) {
+	info, err := impl.OsLstat(root)
+	rootExists := err == nil
+	if rootExists {
+		entries, readErr := impl.IoutilReadDir(root)
+		if !info.IsDir() || readErr != nil || len(entries) > 0 {
+			return errors.New(fmt.Sprintf("staging requires root %s to not exist or be an empty directory", root))
+		}
+	} else if !os.IsNotExist(err) {
+		return
+	}
+
+	c := newCreation(root, options)
+	missingParents, err := missingAncestors(filepath.Dir(root))
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			removeCreatedParents(c.disk, missingParents)
+		}
+	}()
+	if err = c.disk.MkdirAll("", filepath.Dir(root), 0777); err != nil {
+		return
+	}
+	staging, err := c.disk.TempDir(filepath.Dir(root), "."+filepath.Base(root)+".staging-")
+	if err != nil {
+		return
+	}
+	defer removeAllWritable(c.disk, staging)
+
+	//created with MkdirAll rather than TempDir, so that it gets the same mode root would get when created implicitly
+	c.root = filepath.Join(staging, "tree")
+	if err = c.disk.MkdirAll("", c.root, 0777); err != nil {
+		return
+	}
+
+	if err = c.createAndAlign(files...); err != nil {
+		return
+	}
+
+	if !rootExists {
+		return c.disk.Rename(c.root, root)
+	}
+
+	//the tree takes over mode and ownership of the empty root it replaces
+	if err = c.disk.Chmod("", c.root, info.Mode()&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky)); err != nil {
+		return
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		if err = c.disk.Lchown("", c.root, int(stat.Uid), int(stat.Gid)); err != nil {
+			return
+		}
+	}
+	//the empty root ends up in the staging directory, which is removed
+	if err = c.disk.Exchange(c.root, root); err != file.ErrExchangeNotSupported {
+		return
+	}
+	//os.Rename does not replace directories, even empty ones, so for a moment there is no root
+	if err = c.disk.Remove(root); err != nil {
+		return
+	}
+	return c.disk.Rename(c.root, root)
+}
+
+//path and its ancestors (up to the first existing one) which do not exist, deepest first
+func missingAncestors(path string) (missing []string, err error) {
+	for ; ; path = filepath.Dir(path) {
+		if _, err = impl.OsLstat(path); err == nil {
+			return missing, nil
+		} else if !os.IsNotExist(err) {
+			return
+		}
+		missing = append(missing, path)
+		if filepath.Dir(path) == path {
+			return missing, nil
+		}
+	}
+}
+
+//best effort, the error of creation is the one reported
+func removeCreatedParents(disk file.Disk, parents []string) {
+	for _, parent := range parents {
+		if disk.Remove(parent) != nil {
+			return
+		}
+	}
+}
+
+//like os.RemoveAll, but copes with directories without write permission
+func removeAllWritable(disk file.Disk, path string) error {
+	impl.FilepathWalk(path, func(walked string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && info.Mode()&0700 != 0700 {
+			disk.Chmod("", walked, info.Mode().Perm()|0700)
+		}
+		return nil
+	})
+	return disk.RemoveAll(path)
+}
@@ -0,0 +1,282 @@
+package filefactory_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/testingaids/mock"
+)
+
+var _ = Describe("pkg ff create.go unit test", func() {
+
+	var (
+		tempDir,
+		root string
+		fileFactory filefactory.FileFactory
+		files       []file.File
+		failing     mock.File
+		failure     = errors.New("induced failure")
+	)
+
+	BeforeEach(func() {
+		filefactory.ResetImplementation()
+		var err error
+		tempDir, err = ioutil.TempDir("", "create-files-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+		root = filepath.Join(tempDir, "not-yet-existing/root")
+		fileFactory = filefactory.New(attr.Uid(uint32(os.Getuid())), attr.Gid(uint32(os.Getgid())))
+		files = fileFactory.FilesToCreate(
+			def.Dir("read-only", attr.ModePerm(0500)),
+			def.Reg("read-only/regular"),
+			def.Sym("symlink", "read-only"),
+		)
+		failing = mock.NewFile()
+	})
+
+	AfterEach(func() {
+		filefactory.ResetImplementation()
+		filepath.Walk(tempDir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				os.Chmod(path, 0700)
+			}
+			return nil
+		})
+		os.RemoveAll(tempDir)
+	})
+
+	entriesOf := func(dir string) (names []string) {
+		infos, err := ioutil.ReadDir(dir)
+		Expect(err).ShouldNot(HaveOccurred())
+		for _, info := range infos {
+			names = append(names, info.Name())
+		}
+		return
+	}
+
+	It("will tell which definition failed in which phase", func() {
+		failing.CreateFunc = func(root string) error { return failure }
+		err := filefactory.CreateFilesWith(root, filefactory.CreateOptions{}, append(files, failing)...)
+		Expect(err).To(BeAssignableToTypeOf(&filefactory.CreationError{}))
+		Expect(err.(*filefactory.CreationError).File.GetPath()).To(Equal(failing.GetPath()))
+		Expect(err.(*filefactory.CreationError).Phase).To(Equal(filefactory.PhaseCreate))
+		Expect(errors.Is(err, failure)).To(BeTrue())
+		Expect(err).To(MatchError("create phase failed for " + failing.GetPath() + ": induced failure"))
+
+		failing.CreateFunc = func(root string) error { return nil }
+		failing.AlignAttributesFunc = func(owner, mode, times bool, optionalRoot ...string) error { return failure }
+		err = filefactory.CreateFiles(filepath.Join(tempDir, "another-root"), append(files, failing)...)
+		Expect(err.(*filefactory.CreationError).Phase).To(Equal(filefactory.PhaseAlign))
+	})
+
+	Describe("with Rollback", func() {
+		options := filefactory.CreateOptions{Rollback: true}
+
+		It("will remove everything created by the call, including parents and root, if alignment fails", func() {
+			failing.AlignAttributesFunc = func(owner, mode, times bool, optionalRoot ...string) error { return failure }
+
+			err := filefactory.CreateFilesWith(root, options, append(files, failing)...)
+			Expect(errors.Is(err, failure)).To(BeTrue())
+			Expect(err.(*filefactory.CreationError).RollbackErr).ShouldNot(HaveOccurred())
+			Expect(entriesOf(tempDir)).To(BeEmpty())
+		})
+
+		It("will leave files which existed before in place", func() {
+			Expect(os.MkdirAll(filepath.Join(root, "read-only"), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(root, "read-only/regular"), nil, 0600)).To(Succeed())
+			failing.CreateFunc = func(root string) error { return failure }
+
+			err := filefactory.CreateFilesWith(root, options, append(files, failing)...)
+			Expect(errors.Is(err, failure)).To(BeTrue())
+			Expect(entriesOf(root)).To(Equal([]string{"read-only"}))
+			Expect(entriesOf(filepath.Join(root, "read-only"))).To(Equal([]string{"regular"}))
+		})
+
+		It("will not restore contents and attributes of files which existed before", func() {
+			Expect(os.MkdirAll(filepath.Join(root, "read-only"), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(root, "read-only/regular"), []byte("original"), 0600)).To(Succeed())
+			failing.AlignAttributesFunc = func(owner, mode, times bool, optionalRoot ...string) error { return failure }
+
+			err := filefactory.CreateFilesWith(root, options, append(files, failing)...)
+			Expect(errors.Is(err, failure)).To(BeTrue())
+			Expect(err.(*filefactory.CreationError).RollbackErr).ShouldNot(HaveOccurred())
+
+			regular := files[1].(*def.Regular)
+			contents, err := ioutil.ReadFile(filepath.Join(root, "read-only/regular"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).To(Equal(regular.Contents()))
+			info, err := os.Lstat(filepath.Join(root, "read-only"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0500)))
+			Expect(entriesOf(root)).To(Equal([]string{"read-only"}))
+		})
+
+		It("will create files as CreateFiles does if nothing fails", func() {
+			Expect(filefactory.CreateFilesWith(root, options, files...)).To(Succeed())
+			Expect(filefactory.VerifyFiles(root, files...)).To(Succeed())
+		})
+	})
+
+	Describe("with Staging", func() {
+		options := filefactory.CreateOptions{Staging: true}
+
+		It("will rename the complete tree into place and leave no staging directory behind", func() {
+			Expect(filefactory.CreateFilesWith(root, options, files...)).To(Succeed())
+			Expect(filefactory.VerifyFiles(root, files...)).To(Succeed())
+			Expect(entriesOf(filepath.Dir(root))).To(Equal([]string{"root"}))
+		})
+
+		It("will replace empty root directory", func() {
+			Expect(os.MkdirAll(root, 0700)).To(Succeed())
+			Expect(filefactory.CreateFilesWith(root, options, files...)).To(Succeed())
+			Expect(filefactory.VerifyFiles(root, files...)).To(Succeed())
+		})
+
+		It("will leave no trace if creation fails", func() {
+			Expect(os.MkdirAll(filepath.Dir(root), 0700)).To(Succeed())
+			failing.AlignAttributesFunc = func(owner, mode, times bool, optionalRoot ...string) error { return failure }
+
+			err := filefactory.CreateFilesWith(root, options, append(files, failing)...)
+			Expect(errors.Is(err, failure)).To(BeTrue())
+			Expect(entriesOf(filepath.Dir(root))).To(BeEmpty())
+		})
+
+		It("will keep mode of the empty root directory it replaces", func() {
+			Expect(os.MkdirAll(root, 0700)).To(Succeed())
+			Expect(os.Chmod(root, 0750)).To(Succeed())
+			Expect(filefactory.CreateFilesWith(root, options, files...)).To(Succeed())
+
+			info, err := os.Lstat(root)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+			Expect(filefactory.VerifyFiles(root, files...)).To(Succeed())
+		})
+
+		It("will swap the tree with the empty root directory, rather than remove root first", func() {
+			Expect(os.MkdirAll(root, 0700)).To(Succeed())
+			var exchanged []string
+			disk := mock.NewDisk()
+			disk.ExchangeFunc = func(path1, path2 string) error {
+				exchanged = []string{path1, path2}
+				return nil
+			}
+			disk.RemoveFunc = func(path string) error {
+				Expect(path).NotTo(Equal(root))
+				return nil
+			}
+
+			Expect(filefactory.CreateFilesWith(root, filefactory.CreateOptions{Staging: true, Disk: disk}, files...)).To(Succeed())
+			Expect(exchanged).To(HaveLen(2))
+			Expect(exchanged[1]).To(Equal(root))
+		})
+
+		It("will remove the parents of root it created if creation fails", func() {
+			failing.AlignAttributesFunc = func(owner, mode, times bool, optionalRoot ...string) error { return failure }
+
+			err := filefactory.CreateFilesWith(root, options, append(files, failing)...)
+			Expect(errors.Is(err, failure)).To(BeTrue())
+			Expect(entriesOf(tempDir)).To(BeEmpty())
+		})
+
+		It("will refuse to create anything if root is not empty", func() {
+			Expect(os.MkdirAll(filepath.Join(root, "something"), 0700)).To(Succeed())
+
+			err := filefactory.CreateFilesWith(root, options, files...)
+			Expect(err).To(MatchError("staging requires root " + root + " to not exist or be an empty directory"))
+			Expect(entriesOf(root)).To(Equal([]string{"something"}))
+		})
+	})
+
+	Describe("with Umask and SkipAlign", func() {
+
+		modeOf := func(relPath string) os.FileMode {
+			info, err := os.Lstat(filepath.Join(root, relPath))
+			Expect(err).ShouldNot(HaveOccurred())
+			return info.Mode().Perm()
+		}
+
+		BeforeEach(func() {
+			//group and others bits set, so that both umasks have something to clear
+			files = fileFactory.FilesToCreate(
+				def.Dir("shared", attr.ModePerm(0775)),
+				def.Reg("shared/regular"),
+				def.Sym("symlink", "shared"),
+			)
+		})
+
+		It("will create files with the mode defined, regardless of the process umask", func() {
+			previous := syscall.Umask(0077)
+			defer syscall.Umask(previous)
+
+			Expect(filefactory.CreateFilesWith(root, filefactory.CreateOptions{SkipAlign: filefactory.AlignMode}, files...)).To(Succeed())
+			Expect(modeOf("shared")).To(Equal(os.FileMode(0775)))
+			Expect(modeOf("shared/regular")).To(Equal(os.FileMode(0666)))
+		})
+
+		It("will reproduce files created with the given umask, when mode alignment is skipped", func() {
+			options := filefactory.CreateOptions{Umask: 0027, SkipAlign: filefactory.AlignMode}
+			Expect(filefactory.CreateFilesWith(root, options, files...)).To(Succeed())
+			Expect(modeOf("shared")).To(Equal(os.FileMode(0750)))
+			Expect(modeOf("shared/regular")).To(Equal(os.FileMode(0640)))
+		})
+
+		It("will not apply the given umask to parents which are not defined, they get the process umask", func() {
+			previous := syscall.Umask(0022)
+			defer syscall.Umask(previous)
+
+			options := filefactory.CreateOptions{Umask: 0077, SkipAlign: filefactory.AlignMode}
+			Expect(filefactory.CreateFilesWith(root, options, fileFactory.FilesToCreate(def.Reg("implicit/regular"))...)).To(Succeed())
+			Expect(modeOf("implicit")).To(Equal(os.FileMode(0755)))
+			Expect(modeOf("implicit/regular")).To(Equal(os.FileMode(0600)))
+		})
+
+		It("will set the mode defined when aligning", func() {
+			Expect(filefactory.CreateFilesWith(root, filefactory.CreateOptions{Umask: 0027}, files...)).To(Succeed())
+			Expect(filefactory.VerifyFiles(root, files...)).To(Succeed())
+		})
+
+		It("will tell definitions which attributes to align", func() {
+			var aligned [3]bool
+			failing.AlignAttributesFunc = func(owner, mode, times bool, optionalRoot ...string) error {
+				aligned = [3]bool{owner, mode, times}
+				return nil
+			}
+
+			Expect(filefactory.CreateFilesWith(root, filefactory.CreateOptions{SkipAlign: filefactory.AlignOwnership | filefactory.AlignTimes}, failing)).To(Succeed())
+			Expect(aligned).To(Equal([3]bool{false, true, false}))
+		})
+	})
+
+	Describe("with Disk", func() {
+		It("will modify the disk only through it", func() {
+			var created []string
+			disk := mock.NewDisk()
+			disk.MkdirAllFunc = func(diskRoot, relPath string, perm os.FileMode) error {
+				created = append(created, filepath.Join(diskRoot, relPath))
+				return nil
+			}
+			disk.WriteFileFunc = func(diskRoot, relPath string, contents io.Reader, size int64, perm os.FileMode) error {
+				created = append(created, filepath.Join(diskRoot, relPath))
+				return nil
+			}
+
+			Expect(filefactory.CreateFilesWith(root, filefactory.CreateOptions{Disk: disk}, files...)).To(Succeed())
+			Expect(created).To(ContainElement(filepath.Join(root, "read-only/regular")))
+			Expect(filepath.Join(tempDir, "not-yet-existing")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("will refuse definitions which can't be created on it", func() {
+			err := filefactory.CreateFilesWith(root, filefactory.CreateOptions{Disk: mock.NewDisk()}, failing)
+			Expect(err).To(BeAssignableToTypeOf(&filefactory.CreationError{}))
+			Expect(err.(*filefactory.CreationError).Phase).To(Equal(filefactory.PhaseCreate))
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package def
 
 import (
+	"io"
 	"io/ioutil"
 	"os"
 	"github.com/outo/filefactory/file"
@@ -15,16 +16,17 @@ func init() {
 func GetProductionImplementation() Implementation {
 	i := Implementation{
 		//built-in
-		OsLstat:         os.Lstat,
-		OsReadlink:      os.Readlink,
-		OsMkdirAll:      os.MkdirAll,
-		OsSymlink:       os.Symlink,
-		IoutilWriteFile: ioutil.WriteFile,
-		IoutilReadFile:  ioutil.ReadFile,
+		OsLstat:        os.Lstat,
+		OsReadlink:     os.Readlink,
+		IoutilReadFile: ioutil.ReadFile,
 		//custom
 		MetaVerify: func(meta file.Meta, root string) error {
 			return meta.Verify(root)
 		},
+		OpenFile: func(name string) (io.ReadCloser, error) {
+			return os.Open(name)
+		},
+		Disk: file.OsDisk{},
 	}
 	return i
 }
@@ -40,12 +42,13 @@ func ResetImplementation() {
 
 type Implementation struct {
 	//builtin
-	OsLstat         func(name string) (os.FileInfo, error)
-	OsReadlink      func(name string) (string, error)
-	OsMkdirAll      func(path string, perm os.FileMode) error
-	OsSymlink       func(oldname string, newname string) error
-	IoutilWriteFile func(filename string, data []byte, perm os.FileMode) error
-	IoutilReadFile  func(filename string) ([]byte, error)
+	OsLstat        func(name string) (os.FileInfo, error)
+	OsReadlink     func(name string) (string, error)
+	IoutilReadFile func(filename string) ([]byte, error)
 	//custom
 	MetaVerify func(meta file.Meta, root string) error
+	//contents are read from it when verifying
+	OpenFile func(name string) (io.ReadCloser, error)
+	//creates the definitions on Create
+	Disk file.Disk
 }
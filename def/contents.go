@@ -0,0 +1,156 @@
+package def
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/gen"
+)
+
+//contents of the regular file, Size bytes generated with Seed and then changed by each of the Edits in order.
+//All of them are held in memory, Create and Verify stream ContentsReader instead.
+func (f Regular) Contents() []byte {
+	contents := make([]byte, f.ContentsSize())
+	io.ReadFull(f.ContentsReader(), contents)
+	return contents
+}
+
+//Contents as a stream of ContentsSize bytes, generated as they are read.
+//Each of the edits wraps the reader of the contents before it, so only the bytes they add are held in memory.
+func (f Regular) ContentsReader() io.Reader {
+	generator := f.Generator
+	if generator == nil {
+		generator = gen.Legacy
+	}
+	size := f.Size
+	var contents io.Reader = io.LimitReader(generator.NewReader(f.Seed), size)
+	for _, edit := range f.Edits {
+		contents = editedReader(contents, size, edit, generator)
+		size = editedSize(size, edit)
+	}
+	return contents
+}
+
+//size of Contents, without generating them
+func (f Regular) ContentsSize() (size int64) {
+	size = f.Size
+	for _, edit := range f.Edits {
+		size = editedSize(size, edit)
+	}
+	return
+}
+
+func editedSize(size int64, edit attr.ContentEdit) int64 {
+	switch e := edit.(type) {
+	case attr.Append:
+		return size + int64(len(e))
+	case attr.Insert:
+		return maxSize(size, e.Offset) + int64(len(e.Bytes))
+	case attr.Overwrite:
+		return maxSize(size, e.Offset+int64(len(e.Bytes)))
+	case attr.Truncate:
+		return int64(e)
+	case attr.Segment:
+		return size + e.Size
+	}
+	return size
+}
+
+func maxSize(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+//contents is a stream of size bytes, the edit is applied to it as it is read
+func editedReader(contents io.Reader, size int64, edit attr.ContentEdit, generator gen.Generator) io.Reader {
+	switch e := edit.(type) {
+	case attr.Append:
+		return io.MultiReader(contents, bytes.NewReader(e))
+	case attr.Insert:
+		if e.Offset >= size {
+			return io.MultiReader(contents, zeros(e.Offset-size), bytes.NewReader(e.Bytes))
+		}
+		return io.MultiReader(io.LimitReader(contents, e.Offset), bytes.NewReader(e.Bytes), contents)
+	case attr.Overwrite:
+		if e.Offset >= size {
+			return io.MultiReader(contents, zeros(e.Offset-size), bytes.NewReader(e.Bytes))
+		}
+		return io.MultiReader(io.LimitReader(contents, e.Offset), bytes.NewReader(e.Bytes), &skippingReader{contents, int64(len(e.Bytes))})
+	case attr.Truncate:
+		if int64(e) <= size {
+			return io.LimitReader(contents, int64(e))
+		}
+		return io.MultiReader(contents, zeros(int64(e)-size))
+	case attr.Segment:
+		return io.MultiReader(contents, io.LimitReader(generator.NewReader(e.Seed), e.Size))
+	}
+	return contents
+}
+
+//zero bytes fill the gap, as they would in a file written past its end
+func zeros(size int64) io.Reader {
+	return io.LimitReader(zeroReader{}, size)
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+//discards the first skip bytes of the underlying reader
+type skippingReader struct {
+	io.Reader
+	skip int64
+}
+
+func (r *skippingReader) Read(p []byte) (int, error) {
+	if r.skip > 0 {
+		skipped, err := io.CopyN(ioutil.Discard, r.Reader, r.skip)
+		r.skip -= skipped
+		if err != nil {
+			return 0, err
+		}
+	}
+	return r.Reader.Read(p)
+}
+
+//on top of problems of Meta, reports edits which can't be applied
+func (f Regular) Validate() error {
+	var problems []error
+	if err := f.Meta.Validate(); err != nil {
+		problems = append(problems, err.(*file.DefinitionError).Problems...)
+	}
+	for _, edit := range f.Edits {
+		if offset := contentEditOffset(edit); offset < 0 {
+			problems = append(problems, errors.New(fmt.Sprintf("def.Reg: %s, negative offset or size", edit)))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &file.DefinitionError{Path: f.Path, Problems: problems}
+}
+
+func contentEditOffset(edit attr.ContentEdit) int64 {
+	switch e := edit.(type) {
+	case attr.Insert:
+		return e.Offset
+	case attr.Overwrite:
+		return e.Offset
+	case attr.Truncate:
+		return int64(e)
+	case attr.Segment:
+		return e.Size
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+package def_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/diff"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/gen"
+	"github.com/outo/filefactory/verify"
+)
+
+var _ = Describe("pkg def contents.go unit test", func() {
+
+	var root string
+
+	BeforeEach(func() {
+		def.ResetImplementation()
+		var err error
+		root, err = ioutil.TempDir("", "contents-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	regular := func(attributes ...interface{}) *def.Regular {
+		fileFactory := filefactory.New(attr.Uid(uint32(os.Getuid())), attr.Gid(uint32(os.Getgid())))
+		return fileFactory.FilesToCreate(def.Reg("regular", attributes...))[0].(*def.Regular)
+	}
+
+	seeded := func(size, seed int64) []byte {
+		return def.ProvidePseudoRandomBytes(size, seed)
+	}
+
+	join := func(parts ...[]byte) (joined []byte) {
+		for _, part := range parts {
+			joined = append(joined, part...)
+		}
+		return
+	}
+
+	It("will apply edits to the seeded bytes in the order given", func() {
+		base := seeded(10, 1)
+		Expect(regular(attr.Size(10), attr.Seed(1), attr.AppendString("log")).Contents()).To(Equal(join(base, []byte("log"))))
+		Expect(regular(attr.Size(10), attr.Seed(1), attr.InsertAt(4, []byte("in"))).Contents()).To(Equal(join(base[:4], []byte("in"), base[4:])))
+		Expect(regular(attr.Size(10), attr.Seed(1), attr.OverwriteAt(8, []byte("over"))).Contents()).To(Equal(join(base[:8], []byte("over"))))
+		Expect(regular(attr.Size(10), attr.Seed(1), attr.TruncateTo(3)).Contents()).To(Equal(base[:3]))
+		Expect(regular(attr.Size(10), attr.Seed(1), attr.Concat(5, 2)).Contents()).To(Equal(join(base, seeded(5, 2))))
+		Expect(regular(attr.Size(10), attr.Seed(1), attr.TruncateTo(2), attr.AppendString("a"), attr.OverwriteAt(0, []byte("b"))).Contents()).To(Equal(join([]byte("b"), base[1:2], []byte("a"))))
+	})
+
+	It("will fill gaps past the end with zero bytes", func() {
+		Expect(regular(attr.Size(0), attr.InsertAt(2, []byte("in"))).Contents()).To(Equal([]byte("\x00\x00in")))
+		Expect(regular(attr.Size(0), attr.OverwriteAt(1, []byte("o"))).Contents()).To(Equal([]byte("\x00o")))
+		Expect(regular(attr.Size(0), attr.TruncateTo(3)).Contents()).To(Equal([]byte("\x00\x00\x00")))
+	})
+
+	It("will tell the size of the contents without generating them", func() {
+		for _, edits := range [][]interface{}{
+			{},
+			{attr.AppendString("log"), attr.Concat(7, 3)},
+			{attr.InsertAt(30, []byte("in")), attr.InsertAt(1, []byte("in"))},
+			{attr.OverwriteAt(15, []byte("over")), attr.OverwriteAt(50, []byte("over"))},
+			{attr.TruncateTo(5), attr.TruncateTo(40)},
+		} {
+			f := regular(append([]interface{}{attr.Size(20)}, edits...)...)
+			Expect(f.ContentsSize()).To(Equal(int64(len(f.Contents()))))
+		}
+	})
+
+	It("will create and verify the edited contents", func() {
+		f := regular(attr.Seed(5), attr.Concat(10, 6), attr.AppendString("appended"))
+		Expect(f.Create(root)).To(Succeed())
+		Expect(f.AlignAttributes(true, true, true, root)).To(Succeed())
+
+		actual, err := ioutil.ReadFile(filepath.Join(root, "regular"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(actual).To(Equal(join(seeded(20, 5), seeded(10, 6), []byte("appended"))))
+		Expect(f.Verify(root)).To(Succeed())
+	})
+
+	It("will report the first differing offset when contents do not match the edits", func() {
+		Expect(regular(attr.Seed(5)).Create(root)).To(Succeed())
+
+		err := regular(attr.Seed(5), attr.OverwriteAt(12, []byte("patch")), verify.ModifiedTime(false), verify.AccessedTime(false)).Verify(root)
+		Expect(err).To(BeAssignableToTypeOf(&verify.Errors{}))
+		verr := err.(*verify.Errors)
+		Expect(verr.CombinedFileDifference).To(Equal(diff.Contents))
+		Expect(verr.Errors[0].Offset).To(BeEquivalentTo(12))
+	})
+
+	It("will stream edits of contents spanning many chunks the way they apply in memory", func() {
+		base := seeded(200000, 1)
+		edited := join(base[:70000], []byte("in"), base[70000:])
+		edited = join(edited[:100000], []byte("over"), edited[100004:])
+		edited = edited[:150000]
+
+		f := regular(attr.Size(200000), attr.Seed(1), attr.InsertAt(70000, []byte("in")), attr.OverwriteAt(100000, []byte("over")), attr.TruncateTo(150000))
+		Expect(f.ContentsSize()).To(BeEquivalentTo(len(edited)))
+		streamed, err := ioutil.ReadAll(f.ContentsReader())
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(streamed).To(Equal(edited))
+	})
+
+	It("will report the first differing offset past the first chunk compared", func() {
+		Expect(regular(attr.Size(200000), attr.Seed(5)).Create(root)).To(Succeed())
+
+		err := regular(attr.Size(200000), attr.Seed(5), attr.OverwriteAt(150000, []byte("patch")), verify.ModifiedTime(false), verify.AccessedTime(false)).Verify(root)
+		Expect(err).To(BeAssignableToTypeOf(&verify.Errors{}))
+		Expect(err.(*verify.Errors).Errors[0].Offset).To(BeEquivalentTo(150000))
+
+		err = regular(attr.Size(200001), attr.Seed(5), verify.Size(false), verify.ModifiedTime(false), verify.AccessedTime(false)).Verify(root)
+		Expect(err).To(BeAssignableToTypeOf(&verify.Errors{}))
+		Expect(err.(*verify.Errors).Errors[0].Offset).To(BeEquivalentTo(200000))
+	})
+
+	It("will generate the seeded bytes, and segments, with the generator given", func() {
+		Expect(regular(attr.Size(10), attr.Seed(1)).Contents()).To(Equal(gen.Bytes(gen.Legacy, 10, 1)))
+		Expect(regular(attr.Size(10), attr.Seed(1), gen.PCG32V1, attr.Concat(6, 2)).Contents()).To(Equal(join(gen.Bytes(gen.PCG32V1, 10, 1), gen.Bytes(gen.PCG32V1, 6, 2))))
+
+		f := filefactory.New(gen.PCG32V1).FilesToCreate(def.Reg("regular"))[0].(*def.Regular)
+		Expect(f.Generator).To(Equal(gen.PCG32V1))
+		Expect(f.Validate()).To(Succeed())
+	})
+
+	It("will report negative offsets and sizes as invalid definition", func() {
+		err := regular(attr.InsertAt(-1, nil), attr.TruncateTo(-2), attr.AppendString("fine")).Validate()
+		Expect(err).To(BeAssignableToTypeOf(&file.DefinitionError{}))
+		Expect(err).To(MatchError("invalid definition of regular: def.Reg: insert 0 bytes at -1, negative offset or size; def.Reg: truncate to -2, negative offset or size"))
+
+		Expect(regular(attr.AppendString("fine")).Validate()).To(Succeed())
+	})
+})
+
+func benchmarkRegular(b *testing.B, size int64, verifyToo bool) {
+	root, err := ioutil.TempDir("", "contents-benchmark-")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	fileFactory := filefactory.New(attr.Uid(uint32(os.Getuid())), attr.Gid(uint32(os.Getgid())), gen.PCG32V1)
+	f := fileFactory.FilesToCreate(def.Reg("large", attr.Size(size), attr.AppendString("tail"), verify.ModifiedTime(false), verify.AccessedTime(false)))[0]
+	b.SetBytes(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := f.Create(root); err != nil {
+			b.Fatal(err)
+		}
+		if verifyToo {
+			if err := f.Verify(root); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkRegularCreate64MiB(b *testing.B) {
+	benchmarkRegular(b, 64<<20, false)
+}
+
+func BenchmarkRegularCreateAndVerify64MiB(b *testing.B) {
+	benchmarkRegular(b, 64<<20, true)
+}
@@ -20,16 +20,11 @@ func Dir(relPath string, extraFileSpecificAttributes ...interface{}) filefactory
 			attr.ModePerm(0777),
 		}
 
-		combined :=
-			append(hardcodedFileFactoryDefaults,
-				append(fileSpecificDefaults,
-					append(extraFileFactoryDefaults,
-						extraFileSpecificAttributes...
-					)...
-				)...
-			)
+		combined := Combine(hardcodedFileFactoryDefaults, fileSpecificDefaults, extraFileFactoryDefaults, extraFileSpecificAttributes)
 
 		directory.Populate(relPath, combined...)
+		directory.RejectUnknownAttributes("def.Dir", extraFileSpecificAttributes, nil)
+		directory.RejectUnknownAttributes("filefactory.New", extraFileFactoryDefaults, isKnownToAnyConstructor)
 
 		//has to be if it is a directory
 		directory.Mode &= ^os.ModeType
@@ -40,17 +35,34 @@ func Dir(relPath string, extraFileSpecificAttributes ...interface{}) filefactory
 }
 
 func (f Directory) Create(root string) (err error) {
-	path := filepath.Join(root, f.Path)
+	return f.CreateOn(impl.Disk, root)
+}
+
+func (f Directory) CreateOn(disk file.Disk, root string) (err error) {
+	path, err := file.ResolveBeneath(root, f.Path, true)
+	if err != nil {
+		return
+	}
+
+	err = disk.MkdirAll(root, filepath.Dir(f.Path), 0777)
+	if err != nil {
+		return
+	}
+
+	err = disk.MkdirAll(root, f.Path, f.Mode)
+	if err != nil {
+		return
+	}
 
-	dir := filepath.Dir(path)
-	err = impl.OsMkdirAll(dir, 0777)
+	//mode passed to MkdirAll is filtered with umask (and not applied at all if the directory exists)
+	err = disk.Chmod(root, f.Path, f.Mode)
 	if err != nil {
 		return
 	}
 
-	return impl.OsMkdirAll(path, f.Mode)
+	return f.ApplyOnCreateOn(disk, path)
 }
 
 func (f Directory) Verify(root string) (err error) {
-	return impl.MetaVerify(f.Meta, root)
+	return Verify(f.Meta, root, nil)
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 	"github.com/outo/filefactory/def"
 	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/testingaids/mock"
 	"github.com/outo/filefactory/attr"
 )
 
@@ -17,18 +18,18 @@ var _ = Describe("pkg def file_directory.go unit test", func() {
 	var (
 		noError,
 		anError error
+		disk *mock.Disk
 	)
 
 	BeforeEach(func() {
 		def.ResetImplementation()
 		anError = errors.New("just an error, not significant what it is")
+		disk = mock.NewDisk()
 		def.MockForTest(func(modifyThis *def.Implementation) {
 			modifyThis.MetaVerify = func(fileMeta file.Meta, root string) error {
 				return noError
 			}
-			modifyThis.OsMkdirAll = func(path string, perm os.FileMode) error {
-				return noError
-			}
+			modifyThis.Disk = disk
 		})
 	})
 
@@ -71,7 +72,7 @@ var _ = Describe("pkg def file_directory.go unit test", func() {
 	})
 
 	Describe("Directory.Create", func() {
-		It("will invoke os.MkdirAll with path joined with root and mode matching this file", func() {
+		It("will invoke Disk.MkdirAll with path under root with root and mode matching this file", func() {
 			const (
 				expectedMode         = os.FileMode(123)
 				expectedRoot         = "/an/example/root/path"
@@ -84,13 +85,11 @@ var _ = Describe("pkg def file_directory.go unit test", func() {
 
 			var actualPaths []string
 			var actualModes []os.FileMode
-			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.OsMkdirAll = func(path string, perm os.FileMode) error {
-					actualPaths = append(actualPaths, path)
-					actualModes = append(actualModes, perm)
-					return noError
-				}
-			})
+			disk.MkdirAllFunc = func(root, relPath string, perm os.FileMode) error {
+				actualPaths = append(actualPaths, filepath.Join(root, relPath))
+				actualModes = append(actualModes, perm)
+				return noError
+			}
 
 			dir.Create(expectedRoot)
 
@@ -103,14 +102,50 @@ var _ = Describe("pkg def file_directory.go unit test", func() {
 				expectedMode,
 			))
 		})
-		It("will return os.MkdirAll error", func() {
+		It("will invoke Disk.Chmod with mode matching this file, so that it does not depend on umask", func() {
+			dir := def.Directory{}
+			dir.Mode = os.ModeDir | os.ModeSetgid | 0750
+			dir.Path = "relative/path"
+
+			actualPath := ""
+			actualMode := os.FileMode(0)
+			disk.ChmodFunc = func(root, relPath string, mode os.FileMode) error {
+				actualPath = filepath.Join(root, relPath)
+				actualMode = mode
+				return noError
+			}
+
+			Expect(dir.Create("/an/example/root/path")).To(Succeed())
+			Expect(actualPath).To(Equal("/an/example/root/path/relative/path"))
+			Expect(actualMode).To(Equal(dir.Mode))
+		})
+		It("will return Disk.Chmod error", func() {
+			dir := def.Directory{}
+			expectedError := errors.New("os.Chmod error")
+			disk.ChmodFunc = func(root, relPath string, mode os.FileMode) error {
+				return expectedError
+			}
+
+			actualError := dir.Create("does not matter")
+			Expect(actualError).Should(MatchError(expectedError))
+		})
+		It("will refuse Path leading outside of root, without creating anything", func() {
+			dir := def.Directory{}
+			dir.Path = "../escape"
+			disk.MkdirAllFunc = func(root, relPath string, perm os.FileMode) error {
+				Fail("Disk.MkdirAll should not be invoked")
+				return nil
+			}
+
+			actualError := dir.Create("/an/example/root/path")
+			Expect(actualError).Should(BeAssignableToTypeOf(&file.PathEscapeError{}))
+		})
+		It("will return Disk.MkdirAll error", func() {
 			dir := def.Directory{}
 			expectedError := errors.New("os.MkdirAll error")
-			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.OsMkdirAll = func(path string, perm os.FileMode) error {
-					return expectedError
-				}
-			})
+			disk.MkdirAllFunc = func(root, relPath string, perm os.FileMode) error {
+				return expectedError
+			}
 
 			actualError := dir.Create("does not matter")
 			Expect(actualError).Should(MatchError(expectedError))
@@ -156,4 +191,11 @@ var _ = Describe("pkg def file_directory.go unit test", func() {
 		Expect(actual.GetModified()).To(BeTemporally("==", expected.GetModified()))
 		Expect(actual.GetAccessed()).To(BeTemporally("==", expected.GetAccessed()))
 	})
+	It("will report attributes Dir does not recognise upon Validate, be it file specific ones or factory wide ones no constructor recognises", func() {
+		valid := def.Dir("expected/path", attr.Uid(3))(nil, []interface{}{attr.Size(4)})
+		Expect(valid.(file.Validator).Validate()).ShouldNot(HaveOccurred())
+
+		invalid := def.Dir("expected/path", attr.Size(3))(nil, []interface{}{"misspelt"})
+		Expect(invalid.(file.Validator).Validate()).To(MatchError("invalid definition of expected/path: def.Dir: unknown attribute type attr.Size; filefactory.New: unknown attribute type string"))
+	})
 })
@@ -3,23 +3,29 @@ package def
 import (
 	"errors"
 	"fmt"
-	"math/rand"
 	"os"
 	"path/filepath"
-	"bytes"
 	"encoding/base64"
+	"io"
 	"github.com/outo/filefactory/file"
 	"github.com/outo/filefactory"
 	"github.com/outo/filefactory/attr"
 	"github.com/outo/filefactory/verify"
 	"github.com/outo/filefactory/diff"
-	"math"
+	"github.com/outo/filefactory/gen"
+	"github.com/outo/filefactory/match"
 )
 
 type Regular struct {
 	file.Meta
 	Size int64
 	Seed int64
+	//if present, contents are verified with these instead of Seed and Size generated bytes
+	Matchers []match.Matcher
+	//applied in order to Seed and Size generated bytes, see Contents
+	Edits []attr.ContentEdit
+	//of the Seed and Size bytes, gen.Legacy if nil
+	Generator gen.Generator
 }
 
 func Reg(relPath string, extraFileSpecificAttributes ...interface{}) filefactory.DefinitionConstructor {
@@ -31,16 +37,11 @@ func Reg(relPath string, extraFileSpecificAttributes ...interface{}) filefactory
 			attr.Size(20),
 		}
 
-		combined :=
-			append(hardcodedFileFactoryDefaults,
-				append(fileSpecificDefaults,
-					append(extraFileFactoryDefaults,
-					extraFileSpecificAttributes...
-				)...
-			)...
-		)
+		combined := Combine(hardcodedFileFactoryDefaults, fileSpecificDefaults, extraFileFactoryDefaults, extraFileSpecificAttributes)
 
 		regular.Populate(relPath, combined...)
+		regular.RejectUnknownAttributes("def.Reg", extraFileSpecificAttributes, isRegularAttribute)
+		regular.RejectUnknownAttributes("filefactory.New", extraFileFactoryDefaults, isKnownToAnyConstructor)
 
 		for _, attribute := range combined {
 			switch catt := attribute.(type) {
@@ -48,9 +49,20 @@ func Reg(relPath string, extraFileSpecificAttributes ...interface{}) filefactory
 				regular.Size = int64(catt)
 			case attr.Seed:
 				regular.Seed = int64(catt)
+			case match.Matcher:
+				regular.Matchers = append(regular.Matchers, catt)
+			case attr.ContentEdit:
+				regular.Edits = append(regular.Edits, catt)
+			case gen.Generator:
+				regular.Generator = catt
 			}
 		}
 
+		//size of contents described by matchers is unknown, unless instructed otherwise it won't be verified
+		if len(regular.Matchers) > 0 {
+			regular.VerificationInstructions = append([]verify.Instruction{verify.Size(false)}, regular.VerificationInstructions...)
+		}
+
 		//otherwise it is not a regular file
 		regular.Mode &= ^os.ModeType
 
@@ -58,69 +70,158 @@ func Reg(relPath string, extraFileSpecificAttributes ...interface{}) filefactory
 	}
 }
 
+//attributes recognised by Reg on top of those recognised by file.Meta
+func isRegularAttribute(attribute interface{}) bool {
+	switch attribute.(type) {
+	case attr.Size, attr.Seed, match.Matcher, attr.ContentEdit, gen.Generator:
+		return true
+	}
+	return false
+}
+
+//factory wide defaults apply to definitions of any type, so they only need to be recognised by one of the constructors
+func isKnownToAnyConstructor(attribute interface{}) bool {
+	return isRegularAttribute(attribute)
+}
+
 func (f Regular) String() string {
-	return fmt.Sprintf("%s %d", f.Meta.String(), f.Size)
+	return fmt.Sprintf("%s %d", f.Meta.String(), f.ContentsSize())
 }
 
 func (f Regular) Create(root string) (err error) {
-	path := filepath.Join(root, f.Path)
-	dir := filepath.Dir(path)
-	err = impl.OsMkdirAll(dir, 0777)
+	return f.CreateOn(impl.Disk, root)
+}
+
+func (f Regular) CreateOn(disk file.Disk, root string) (err error) {
+	path, err := file.ResolveBeneath(root, f.Path, true)
+	if err != nil {
+		return
+	}
+	err = disk.MkdirAll(root, filepath.Dir(f.Path), 0777)
 	if err != nil {
 		return
 	}
 
-	return impl.IoutilWriteFile(path, ProvidePseudoRandomBytes(f.Size, f.Seed), f.Mode)
+	err = disk.WriteFile(root, f.Path, f.ContentsReader(), f.ContentsSize(), f.Mode)
+	if err != nil {
+		return
+	}
+
+	//mode passed to WriteFile is filtered with umask (and not applied at all if the file exists)
+	err = disk.Chmod(root, f.Path, f.Mode)
+	if err != nil {
+		return
+	}
+
+	return f.ApplyOnCreateOn(disk, path)
 }
 
+//bytes of gen.Legacy, the generator used unless another one is given as attribute (held in memory, see gen.Bytes)
 func ProvidePseudoRandomBytes(size, seed int64) (bs []byte) {
-	rnd := rand.New(rand.NewSource(seed))
-	bs = make([]byte, size)
-	rnd.Read(bs)
-	return
+	return gen.Bytes(gen.Legacy, size, seed)
 }
 
 func (f Regular) Verify(root string) (err error) {
-	verr := &verify.Errors{}
-
-	err = impl.MetaVerify(f.Meta, root)
-	if err = verr.Merge(err); err != nil {
-		return
-	}
-
-	if verr.IsFileNotPresentOrNotAccessible() {
-		return verr
-	} else if verr.IsFileTypeUnexpected() {
-		return verr
-	}
+	return Verify(f.Meta, root, f.verifySizeAndContents)
+}
 
-	absolutePath := filepath.Join(root, f.Path)
+func (f Regular) verifySizeAndContents(verr *verify.Errors, absolutePath string) (err error) {
 	fi, err := impl.OsLstat(absolutePath)
 	if err != nil {
 		return
 	}
 
 	if f.Should(verify.Size(true)) {
-		if fi.Size() != f.Size {
-			verr.Add(diff.Size, absolutePath, errors.New(fmt.Sprintf("expected %d, actual %d", f.Size, fi.Size())))
+		if expectedSize := f.ContentsSize(); fi.Size() != expectedSize {
+			verr.AddError(verify.NewDifference(diff.Size, absolutePath, f.Path, expectedSize, fi.Size(), errors.New(fmt.Sprintf("expected %d, actual %d", expectedSize, fi.Size()))))
 		}
 	}
 
 	if f.Should(verify.Contents(true)) {
-		actualBytes, err := impl.IoutilReadFile(absolutePath)
+		if len(f.Matchers) > 0 {
+			actualBytes, err := impl.IoutilReadFile(absolutePath)
+			if err != nil {
+				return err
+			}
+			for _, matcher := range f.Matchers {
+				if err := matcher.Match(actualBytes); err != nil {
+					verr.AddError(verify.NewDifference(diff.Contents, absolutePath, f.Path, matcher.String(), nil, err))
+				}
+			}
+			return nil
+		}
+
+		actual, err := impl.OpenFile(absolutePath)
 		if err != nil {
 			return err
 		}
-		expectedBytes := ProvidePseudoRandomBytes(f.Size, f.Seed)
-		if !bytes.Equal(actualBytes, expectedBytes) {
-			expectedBytesSampleLength := int(math.Min(50, float64(len(expectedBytes))))
-			actualBytesSampleLength := int(math.Min(50, float64(len(actualBytes))))
-			verr.Add(diff.Contents, absolutePath, errors.New(fmt.Sprintf("base64(bytes[:<=50]) for expected %s, actual %s",
-				base64.StdEncoding.EncodeToString(expectedBytes[:expectedBytesSampleLength]),
-				base64.StdEncoding.EncodeToString(actualBytes[:actualBytesSampleLength]),
+		defer actual.Close()
+		offset, expectedHead, actualHead, same, err := compareContents(f.ContentsReader(), actual)
+		if err != nil {
+			return err
+		}
+		if !same {
+			contentsError := verify.NewDifference(diff.Contents, absolutePath, f.Path, nil, nil, errors.New(fmt.Sprintf("first difference at offset %d, base64(bytes[:<=50]) for expected %s, actual %s",
+				offset,
+				base64.StdEncoding.EncodeToString(expectedHead),
+				base64.StdEncoding.EncodeToString(actualHead),
 			)))
+			contentsError.Offset = offset
+			verr.AddError(contentsError)
 		}
 	}
+	return
+}
 
-	return verr.MapToNilIfNone()
+//contents are compared chunk by chunk, so that large files are not held in memory
+const compareChunkSize = 64 * 1024
+
+//length of the heads returned by compareContents, sampled in the error message
+const headSampleSize = 50
+
+//Tells whether the streams are the same and if not, the offset they first differ at.
+//The first (up to) 50 bytes of each of them are returned too.
+func compareContents(expected, actual io.Reader) (offset int64, expectedHead, actualHead []byte, same bool, err error) {
+	expectedChunk := make([]byte, compareChunkSize)
+	actualChunk := make([]byte, compareChunkSize)
+	for {
+		expectedLen, expectedErr := io.ReadFull(expected, expectedChunk)
+		actualLen, actualErr := io.ReadFull(actual, actualChunk)
+		for _, readErr := range []error{expectedErr, actualErr} {
+			if readErr != nil && readErr != io.EOF && readErr != io.ErrUnexpectedEOF {
+				err = readErr
+				return
+			}
+		}
+
+		if offset == 0 {
+			expectedHead = append([]byte{}, expectedChunk[:minLength(expectedLen, headSampleSize)]...)
+			actualHead = append([]byte{}, actualChunk[:minLength(actualLen, headSampleSize)]...)
+		}
+
+		differsAt := firstDifferingOffset(expectedChunk[:expectedLen], actualChunk[:actualLen])
+		if expectedLen != actualLen || differsAt < int64(expectedLen) {
+			return offset + differsAt, expectedHead, actualHead, false, nil
+		}
+		if expectedLen < compareChunkSize {
+			return offset + int64(expectedLen), expectedHead, actualHead, true, nil
+		}
+		offset += int64(expectedLen)
+	}
+}
+
+func minLength(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+//offset of the first byte which differs, or length of the shorter one if it is a prefix of the other
+func firstDifferingOffset(expected, actual []byte) int64 {
+	i := 0
+	for i < len(expected) && i < len(actual) && expected[i] == actual[i] {
+		i++
+	}
+	return int64(i)
 }
@@ -1,7 +1,10 @@
 package def_test
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"io/ioutil"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/outo/filefactory/testingaids/mock"
@@ -13,6 +16,7 @@ import (
 	"github.com/outo/filefactory/verify"
 	"github.com/outo/filefactory/diff"
 	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/match"
 )
 
 var _ = Describe("pkg def file_regular.go unit test", func() {
@@ -20,20 +24,23 @@ var _ = Describe("pkg def file_regular.go unit test", func() {
 	var (
 		noError,
 		anError error
+		disk *mock.Disk
 	)
 
 	BeforeEach(func() {
 		def.ResetImplementation()
 		anError = errors.New("just an error, not significant what it is")
+		disk = mock.NewDisk()
 		def.MockForTest(func(modifyThis *def.Implementation) {
+			modifyThis.Disk = disk
 			modifyThis.MetaVerify = func(fileMeta file.Meta, root string) error {
 				return noError
 			}
 			modifyThis.OsLstat = func(name string) (os.FileInfo, error) {
 				return mock.NewFileInfo().WithSize(20), noError
 			}
-			modifyThis.IoutilReadFile = func(filename string) ([]byte, error) {
-				return def.ProvidePseudoRandomBytes(20, 18), noError
+			modifyThis.OpenFile = func(name string) (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(def.ProvidePseudoRandomBytes(20, 18))), noError
 			}
 		})
 	})
@@ -195,8 +202,8 @@ var _ = Describe("pkg def file_regular.go unit test", func() {
 				modifyThis.MetaVerify = func(fileMeta file.Meta, root string) error {
 					return &verificationErrors
 				}
-				modifyThis.IoutilReadFile = func(filename string) ([]byte, error) {
-					return def.ProvidePseudoRandomBytes(20, 99199), noError
+				modifyThis.OpenFile = func(name string) (io.ReadCloser, error) {
+					return ioutil.NopCloser(bytes.NewReader(def.ProvidePseudoRandomBytes(20, 99199))), noError
 				}
 			})
 
@@ -213,10 +220,70 @@ var _ = Describe("pkg def file_regular.go unit test", func() {
 			Expect(actualVerificationErrors.HasDifference(diff.AccTime, "some path")).To(BeTrue())
 			Expect(actualVerificationErrors.HasDifference(diff.Contents, filepath.Join(expectedRoot, "file-with-different-contents"))).To(BeTrue())
 		})
-		It("will return ioutil.ReadFile error immediately", func() {
-			expectedError := errors.New("ioutil.ReadFile error")
+		It("will report the offset contents first differ at", func() {
+			expectedBytes := def.ProvidePseudoRandomBytes(20, 99999)
+			actualBytes := append([]byte{}, expectedBytes...)
+			actualBytes[13] ^= 0xff
 			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.IoutilReadFile = func(filename string) ([]byte, error) {
+				modifyThis.MetaVerify = func(fileMeta file.Meta, root string) error {
+					return nil
+				}
+				modifyThis.OpenFile = func(name string) (io.ReadCloser, error) {
+					return ioutil.NopCloser(bytes.NewReader(actualBytes)), noError
+				}
+			})
+
+			regular := def.Regular{}
+			regular.Size = 20
+			regular.Seed = 99999
+			actualError := regular.Verify(expectedRoot)
+			Expect(actualError).Should(HaveOccurred())
+
+			var contentsError verify.Error
+			Expect(errors.As(actualError, &contentsError)).To(BeTrue())
+			Expect(contentsError.FileDifference).To(Equal(diff.Contents))
+			Expect(contentsError.Offset).To(Equal(int64(13)))
+			Expect(contentsError.Err.Error()).To(HavePrefix("first difference at offset 13,"))
+		})
+		Describe("given content matchers", func() {
+			BeforeEach(func() {
+				def.MockForTest(func(modifyThis *def.Implementation) {
+					modifyThis.IoutilReadFile = func(filename string) ([]byte, error) {
+						return []byte(`{"key": "value"}`), noError
+					}
+				})
+			})
+
+			It("will verify contents with matchers instead of comparing them to Seed and Size generated bytes", func() {
+				regular := def.Regular{}
+				regular.Size = 20
+				regular.Seed = 18
+				regular.Matchers = []match.Matcher{match.Contains("key"), match.JSONEqual(`{"key":"value"}`)}
+				actualError := regular.Verify(expectedRoot)
+				Expect(actualError).ShouldNot(HaveOccurred())
+			})
+
+			It("will append error to VerificationErrors for each of the matchers that failed", func() {
+				regular := def.Regular{}
+				regular.Path = "file-with-unexpected-contents"
+				regular.Size = 20
+				regular.Matchers = []match.Matcher{match.Contains("key"), match.Prefix("#"), match.Suffix("#")}
+				actualError := regular.Verify(expectedRoot)
+				Expect(actualError).Should(HaveOccurred())
+				Expect(actualError).To(BeAssignableToTypeOf(&verify.Errors{}))
+				actualVerificationErrors := actualError.(*verify.Errors)
+				Expect(actualVerificationErrors.Errors).To(HaveLen(2))
+				Expect(actualVerificationErrors.CombinedFileDifference).To(Equal(diff.Contents))
+				Expect(actualVerificationErrors.Errors[0].Path).To(Equal(filepath.Join(expectedRoot, "file-with-unexpected-contents")))
+				Expect(actualVerificationErrors.Errors[0].Err).To(MatchError(`expected contents to start with "#", actual "{\"key\": \"value\"}"`))
+				Expect(actualVerificationErrors.Errors[1].Err).To(MatchError(`expected contents to end with "#", actual "{\"key\": \"value\"}"`))
+			})
+		})
+
+		It("will return error opening the file immediately", func() {
+			expectedError := errors.New("os.Open error")
+			def.MockForTest(func(modifyThis *def.Implementation) {
+				modifyThis.OpenFile = func(name string) (io.ReadCloser, error) {
 					return nil, expectedError
 				}
 			})
@@ -236,7 +303,7 @@ var _ = Describe("pkg def file_regular.go unit test", func() {
 	})
 
 	Describe("Regular.Create", func() {
-		It("will invoke ioutil.WriteFile with path joined with root, number of bytes and mode matching this file", func() {
+		It("will invoke Disk.WriteFile with path under root, number of bytes and mode matching this file", func() {
 			const (
 				expectedSize         = 2736
 				expectedMode         = os.FileMode(123)
@@ -252,17 +319,15 @@ var _ = Describe("pkg def file_regular.go unit test", func() {
 			actualPath := ""
 			actualDataLength := 0
 			actualMode := os.FileMode(0)
-			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.OsMkdirAll = func(path string, perm os.FileMode) error {
-					return noError
-				}
-				modifyThis.IoutilWriteFile = func(filename string, data []byte, perm os.FileMode) error {
-					actualPath = filename
-					actualDataLength = len(data)
-					actualMode = perm
-					return anError
-				}
-			})
+			disk.WriteFileFunc = func(root, relPath string, contents io.Reader, size int64, perm os.FileMode) error {
+				data, err := ioutil.ReadAll(contents)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(data).To(HaveLen(int(size)))
+				actualPath = filepath.Join(root, relPath)
+				actualDataLength = len(data)
+				actualMode = perm
+				return anError
+			}
 
 			regular.Create(expectedRoot)
 
@@ -270,26 +335,50 @@ var _ = Describe("pkg def file_regular.go unit test", func() {
 			Expect(actualDataLength).To(Equal(expectedSize))
 			Expect(actualMode).To(Equal(expectedMode))
 		})
-		It("will return os.MkdirAll error", func() {
+		It("will refuse Path leading outside of root, without creating anything", func() {
+			regular := def.Regular{}
+			regular.Path = "../escape"
+			disk.MkdirAllFunc = func(root, relPath string, perm os.FileMode) error {
+				Fail("Disk.MkdirAll should not be invoked")
+				return nil
+			}
+
+			actualError := regular.Create("/an/example/root/path")
+			Expect(actualError).Should(BeAssignableToTypeOf(&file.PathEscapeError{}))
+		})
+		It("will return Disk.MkdirAll error", func() {
 			regular := def.Regular{}
 			expectedError := errors.New("os.MkdirAll error")
-			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.OsMkdirAll = func(path string, perm os.FileMode) error {
-					return expectedError
-				}
-			})
+			disk.MkdirAllFunc = func(root, relPath string, perm os.FileMode) error {
+				return expectedError
+			}
 
 			actualError := regular.Create("does not matter")
 			Expect(actualError).Should(MatchError(expectedError))
 		})
-		It("will return ioutil.WriteFile error", func() {
+		It("will invoke Disk.Chmod with mode matching this file, so that it does not depend on umask", func() {
 			regular := def.Regular{}
-			expectedError := errors.New("ioutil.WriteFile error")
-			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.IoutilWriteFile = func(filename string, data []byte, perm os.FileMode) error {
-					return expectedError
-				}
-			})
+			regular.Mode = os.ModeSetuid | 0764
+			regular.Path = "relative/path"
+
+			actualPath := ""
+			actualMode := os.FileMode(0)
+			disk.ChmodFunc = func(root, relPath string, mode os.FileMode) error {
+				actualPath = filepath.Join(root, relPath)
+				actualMode = mode
+				return anError
+			}
+
+			Expect(regular.Create("/an/example/root/path")).Should(MatchError(anError))
+			Expect(actualPath).To(Equal("/an/example/root/path/relative/path"))
+			Expect(actualMode).To(Equal(regular.Mode))
+		})
+		It("will return Disk.WriteFile error", func() {
+			regular := def.Regular{}
+			expectedError := errors.New("Disk.WriteFile error")
+			disk.WriteFileFunc = func(root, relPath string, contents io.Reader, size int64, perm os.FileMode) error {
+				return expectedError
+			}
 
 			actualError := regular.Create("does not matter")
 			Expect(actualError).Should(MatchError(expectedError))
@@ -342,4 +431,26 @@ var _ = Describe("pkg def file_regular.go unit test", func() {
 		Expect(actual.(*def.Regular).Size).To(Equal(expected.Size))
 		Expect(actual.(*def.Regular).Seed).To(Equal(expected.Seed))
 	})
+
+	It("will attach content matchers using constructor and, unless instructed otherwise, will not verify the size", func() {
+		contains := match.Contains("key")
+		actual := def.Reg("expected/path", contains)(nil, nil).(*def.Regular)
+		Expect(actual.Matchers).To(HaveLen(1))
+		Expect(actual.Matchers[0].String()).To(Equal(contains.String()))
+		Expect(actual.Should(verify.Size(true))).To(BeFalse())
+
+		actual = def.Reg("expected/path", contains, verify.Size(true))(nil, nil).(*def.Regular)
+		Expect(actual.Should(verify.Size(true))).To(BeTrue())
+
+		actual = def.Reg("expected/path")(nil, nil).(*def.Regular)
+		Expect(actual.Matchers).To(BeEmpty())
+		Expect(actual.VerificationInstructions).To(BeEmpty())
+	})
+	It("will report attributes Reg does not recognise upon Validate", func() {
+		valid := def.Reg("expected/path", attr.Size(3), attr.Seed(4), match.Contains("key"))(nil, nil)
+		Expect(valid.(file.Validator).Validate()).ShouldNot(HaveOccurred())
+
+		invalid := def.Reg("expected/path", "misspelt")(nil, nil)
+		Expect(invalid.(file.Validator).Validate()).To(MatchError("invalid definition of expected/path: def.Reg: unknown attribute type string"))
+	})
 })
@@ -28,16 +28,11 @@ func Sym(relPath string, linkTarget string, extraFileSpecificAttributes ...inter
 			verify.AccessedTime(false),
 		}
 
-		combined :=
-			append(hardcodedFileFactoryDefaults,
-				append(fileSpecificDefaults,
-					append(extraFileFactoryDefaults,
-						extraFileSpecificAttributes...
-					)...
-				)...
-			)
+		combined := Combine(hardcodedFileFactoryDefaults, fileSpecificDefaults, extraFileFactoryDefaults, extraFileSpecificAttributes)
 
 		symlink.Populate(relPath, combined...)
+		symlink.RejectUnknownAttributes("def.Sym", extraFileSpecificAttributes, nil)
+		symlink.RejectUnknownAttributes("filefactory.New", extraFileFactoryDefaults, isKnownToAnyConstructor)
 
 		//a must for a symlink
 		symlink.Mode &= ^os.ModeType
@@ -52,32 +47,33 @@ func (f Symlink) String() string {
 }
 
 func (f Symlink) Create(root string) (err error) {
-	path := filepath.Join(root, f.Path)
+	return f.CreateOn(impl.Disk, root)
+}
 
-	dir := filepath.Dir(path)
-	err = impl.OsMkdirAll(dir, 0777)
+func (f Symlink) CreateOn(disk file.Disk, root string) (err error) {
+	path, err := file.ResolveBeneath(root, f.Path, false)
 	if err != nil {
 		return
 	}
 
-	return impl.OsSymlink(f.LinkTarget, path)
-}
-
-func (f Symlink) Verify(root string) (err error) {
-	verr := &verify.Errors{}
-
-	err = impl.MetaVerify(f.Meta, root)
-	if err = verr.Merge(err); err != nil {
+	err = disk.MkdirAll(root, filepath.Dir(f.Path), 0777)
+	if err != nil {
 		return
 	}
 
-	if verr.IsFileNotPresentOrNotAccessible() {
-		return verr
-	} else if verr.IsFileTypeUnexpected() {
-		return verr
+	err = disk.Symlink(f.LinkTarget, root, f.Path)
+	if err != nil {
+		return
 	}
 
-	path := filepath.Join(root, f.Path)
+	return f.ApplyOnCreateOn(disk, path)
+}
+
+func (f Symlink) Verify(root string) (err error) {
+	return Verify(f.Meta, root, f.verifyTarget)
+}
+
+func (f Symlink) verifyTarget(verr *verify.Errors, path string) (err error) {
 	linkTarget, err := impl.OsReadlink(path)
 	if err != nil {
 		return
@@ -85,9 +81,8 @@ func (f Symlink) Verify(root string) (err error) {
 
 	if f.Should(verify.SymlinkTarget(true)) {
 		if linkTarget != f.LinkTarget {
-			verr.Add(diff.LinkTarget, path, errors.New(fmt.Sprintf("expected %s, actual %s", f.LinkTarget, linkTarget)))
+			verr.AddError(verify.NewDifference(diff.LinkTarget, path, f.Path, f.LinkTarget, linkTarget, errors.New(fmt.Sprintf("expected %s, actual %s", f.LinkTarget, linkTarget))))
 		}
 	}
-
-	return verr.MapToNilIfNone()
+	return
 }
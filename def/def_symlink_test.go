@@ -9,6 +9,7 @@ import (
 	"time"
 	"github.com/outo/filefactory/def"
 	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/testingaids/mock"
 	"github.com/outo/filefactory/verify"
 	"github.com/outo/filefactory/diff"
 	"github.com/outo/filefactory/attr"
@@ -21,11 +22,13 @@ var _ = Describe("pkg def file_symlink.go unit test", func() {
 	var (
 		noError,
 		anError error
+		disk *mock.Disk
 	)
 
 	BeforeEach(func() {
 		def.ResetImplementation()
 		anError = errors.New("just an error, not significant what it is")
+		disk = mock.NewDisk()
 		def.MockForTest(func(modifyThis *def.Implementation) {
 			modifyThis.MetaVerify = func(fileMeta file.Meta, root string) error {
 				return noError
@@ -33,6 +36,7 @@ var _ = Describe("pkg def file_symlink.go unit test", func() {
 			modifyThis.OsReadlink = func(name string) (string, error) {
 				return retrievedSymlinkTarget, noError
 			}
+			modifyThis.Disk = disk
 		})
 	})
 
@@ -185,7 +189,7 @@ var _ = Describe("pkg def file_symlink.go unit test", func() {
 	})
 
 	Describe("Symlink.Create", func() {
-		It("will invoke os.Symlink with path joined with root and with link target matching this file", func() {
+		It("will invoke Disk.Symlink with path under root with root and with link target matching this file", func() {
 			const (
 				expectedTarget       = "expected/link/target"
 				expectedRoot         = "/an/example/root/path"
@@ -198,42 +202,44 @@ var _ = Describe("pkg def file_symlink.go unit test", func() {
 
 			actualTarget := ""
 			actualSymlinkPath := ""
-			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.OsMkdirAll = func(path string, perm os.FileMode) error {
-					return noError
-				}
-				modifyThis.OsSymlink = func(oldname string, newname string) error {
-					actualTarget = oldname
-					actualSymlinkPath = newname
-					return anError
-				}
-			})
+			disk.SymlinkFunc = func(target, root, relPath string) error {
+				actualTarget = target
+				actualSymlinkPath = filepath.Join(root, relPath)
+				return anError
+			}
 
 			symlink.Create(expectedRoot)
 
 			Expect(actualTarget).To(Equal(expectedTarget))
 			Expect(actualSymlinkPath).To(Equal(filepath.Join(expectedRoot, expectedRelativePath)))
 		})
-		It("will return os.MkdirAll error", func() {
+		It("will refuse Path leading outside of root, without creating anything", func() {
+			symlink := def.Symlink{}
+			symlink.Path = "../escape"
+			disk.MkdirAllFunc = func(root, relPath string, perm os.FileMode) error {
+				Fail("Disk.MkdirAll should not be invoked")
+				return nil
+			}
+
+			actualError := symlink.Create("/an/example/root/path")
+			Expect(actualError).Should(BeAssignableToTypeOf(&file.PathEscapeError{}))
+		})
+		It("will return Disk.MkdirAll error", func() {
 			symlink := def.Symlink{}
 			expectedError := errors.New("os.MkdirAll error")
-			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.OsMkdirAll = func(path string, perm os.FileMode) error {
-					return expectedError
-				}
-			})
+			disk.MkdirAllFunc = func(root, relPath string, perm os.FileMode) error {
+				return expectedError
+			}
 
 			actualError := symlink.Create("does not matter")
 			Expect(actualError).Should(MatchError(expectedError))
 		})
-		It("will return os.Symlink error", func() {
+		It("will return Disk.Symlink error", func() {
 			symlink := def.Symlink{}
 			expectedError := errors.New("os.Symlink error")
-			def.MockForTest(func(modifyThis *def.Implementation) {
-				modifyThis.OsSymlink = func(oldname string, newname string) error {
-					return expectedError
-				}
-			})
+			disk.SymlinkFunc = func(target, root, relPath string) error {
+				return expectedError
+			}
 
 			actualError := symlink.Create("does not matter")
 			Expect(actualError).Should(MatchError(expectedError))
@@ -284,4 +290,8 @@ var _ = Describe("pkg def file_symlink.go unit test", func() {
 		Expect(actual.(*def.Symlink).LinkTarget).To(Equal(expected.LinkTarget))
 	})
 
+	It("will report attributes Sym does not recognise upon Validate", func() {
+		invalid := def.Sym("expected/path", "target", attr.Seed(3))(nil, nil)
+		Expect(invalid.(file.Validator).Validate()).To(MatchError("invalid definition of expected/path: def.Sym: unknown attribute type attr.Seed"))
+	})
 })
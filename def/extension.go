@@ -0,0 +1,43 @@
+package def
+
+import (
+	"path/filepath"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/verify"
+)
+
+//Checks specific to a definition type, path is already joined with root.
+//Differences are to be added to verr, any other error is returned as is.
+type SpecificVerification func(verr *verify.Errors, path string) error
+
+//Merges attributes in the order of precedence (lowest first), as constructors of this package do before Meta.Populate.
+//The result does not share its backing array with any of the arguments, so it is safe to modify.
+func Combine(hardcodedFileFactoryDefaults, fileSpecificDefaults, extraFileFactoryDefaults, extraFileSpecificAttributes []interface{}) []interface{} {
+	combined := make([]interface{}, 0, len(hardcodedFileFactoryDefaults)+len(fileSpecificDefaults)+len(extraFileFactoryDefaults)+len(extraFileSpecificAttributes))
+	combined = append(combined, hardcodedFileFactoryDefaults...)
+	combined = append(combined, fileSpecificDefaults...)
+	combined = append(combined, extraFileFactoryDefaults...)
+	return append(combined, extraFileSpecificAttributes...)
+}
+
+//Verification pipeline of the definitions of this package, for use by custom definition types.
+//The common Meta checks go first (presence, type, mode, ownership, timestamps and custom attributes).
+//Specific ones (may be nil) follow, unless the file is not present or is not of the expected type.
+func Verify(meta file.Meta, root string, specific SpecificVerification) (err error) {
+	verr := &verify.Errors{}
+
+	err = impl.MetaVerify(meta, root)
+	if err = verr.Merge(err); err != nil {
+		return
+	}
+
+	if verr.IsFileNotPresentOrNotAccessible() || verr.IsFileTypeUnexpected() || specific == nil {
+		return verr.MapToNilIfNone()
+	}
+
+	if err = specific(verr, filepath.Join(root, meta.Path)); err != nil {
+		return
+	}
+
+	return verr.MapToNilIfNone()
+}
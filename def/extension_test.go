@@ -0,0 +1,107 @@
+package def_test
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/diff"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/verify"
+)
+
+var _ = Describe("pkg def extension.go unit test", func() {
+
+	Describe("Combine", func() {
+		It("will concatenate attributes in the order of precedence, lowest first", func() {
+			Expect(def.Combine(
+				[]interface{}{attr.Uid(1)},
+				[]interface{}{attr.Uid(2)},
+				nil,
+				[]interface{}{attr.Uid(4), attr.Gid(5)},
+			)).To(Equal([]interface{}{attr.Uid(1), attr.Uid(2), attr.Uid(4), attr.Gid(5)}))
+		})
+		It("will not write into the backing array of any of the arguments", func() {
+			hardcoded := make([]interface{}, 1, 10)
+			hardcoded[0] = attr.Uid(1)
+
+			first := def.Combine(hardcoded, nil, nil, []interface{}{attr.Gid(2)})
+			second := def.Combine(hardcoded, nil, nil, []interface{}{attr.Gid(3)})
+			Expect(first).To(Equal([]interface{}{attr.Uid(1), attr.Gid(2)}))
+			Expect(second).To(Equal([]interface{}{attr.Uid(1), attr.Gid(3)}))
+		})
+	})
+
+	Describe("Verify", func() {
+		const expectedRoot = "/an/example/root"
+
+		var (
+			meta              file.Meta
+			specificInvokedOn []string
+			specific          def.SpecificVerification
+		)
+
+		BeforeEach(func() {
+			def.ResetImplementation()
+			meta = file.Meta{Path: "relative/path"}
+			specificInvokedOn = nil
+			specific = func(verr *verify.Errors, path string) error {
+				specificInvokedOn = append(specificInvokedOn, path)
+				verr.Add(diff.Size, path, errors.New("specific difference"))
+				return nil
+			}
+		})
+
+		AfterEach(func() {
+			def.ResetImplementation()
+		})
+
+		mockMetaVerify := func(err error) {
+			def.MockForTest(func(modifyThis *def.Implementation) {
+				modifyThis.MetaVerify = func(fileMeta file.Meta, root string) error {
+					return err
+				}
+			})
+		}
+
+		It("will run specific checks with path joined with root and combine their differences with common ones", func() {
+			verr := &verify.Errors{}
+			verr.Add(diff.ModePerm, "/an/example/root/relative/path", errors.New("common difference"))
+			mockMetaVerify(verr)
+
+			err := def.Verify(meta, expectedRoot, specific)
+			Expect(specificInvokedOn).To(Equal([]string{"/an/example/root/relative/path"}))
+			Expect(err).To(BeAssignableToTypeOf(&verify.Errors{}))
+			Expect(err.(*verify.Errors).CombinedFileDifference).To(Equal(diff.ModePerm | diff.Size))
+		})
+
+		It("will return nil if neither common nor specific checks found a difference", func() {
+			mockMetaVerify(nil)
+			Expect(def.Verify(meta, expectedRoot, func(verr *verify.Errors, path string) error { return nil })).To(Succeed())
+			Expect(def.Verify(meta, expectedRoot, nil)).To(Succeed())
+		})
+
+		It("will not run specific checks if the file is not present or of unexpected type", func() {
+			for _, difference := range []diff.FileDifference{diff.NotPresentOrNotAccessible, diff.ModeType} {
+				verr := &verify.Errors{}
+				verr.Add(difference, "/an/example/root/relative/path", errors.New("common difference"))
+				mockMetaVerify(verr)
+
+				err := def.Verify(meta, expectedRoot, specific)
+				Expect(err.(*verify.Errors).CombinedFileDifference).To(Equal(difference))
+			}
+			Expect(specificInvokedOn).To(BeEmpty())
+		})
+
+		It("will return non-verification errors of either of the checks as they are", func() {
+			expectedError := errors.New("not a verification error")
+			mockMetaVerify(expectedError)
+			Expect(def.Verify(meta, expectedRoot, specific)).To(MatchError(expectedError))
+			Expect(specificInvokedOn).To(BeEmpty())
+
+			mockMetaVerify(nil)
+			Expect(def.Verify(meta, expectedRoot, func(verr *verify.Errors, path string) error { return expectedError })).To(MatchError(expectedError))
+		})
+	})
+})
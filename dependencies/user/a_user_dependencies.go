@@ -1,6 +1,8 @@
 package user
 
 import (
+	"io/ioutil"
+	"os"
 	"os/user"
 )
 
@@ -19,6 +21,12 @@ func GetProductionImplementation() Implementation {
 		UserGroupIds: func(u *user.User) ([]string, error) {
 			return u.GroupIds()
 		},
+		UserLookup:      user.Lookup,
+		UserLookupGroup: user.LookupGroup,
+		IoutilReadFile:  ioutil.ReadFile,
+		OsGetuid:        os.Getuid,
+		OsGetgid:        os.Getgid,
+		OsGetgroups:     os.Getgroups,
 		//custom
 	}
 }
@@ -34,7 +42,13 @@ func ResetImplementation() {
 
 type Implementation struct {
 	//builtin
-	UserCurrent  func() (*user.User, error)
-	UserGroupIds func(u *user.User) ([]string, error)
+	UserCurrent     func() (*user.User, error)
+	UserGroupIds    func(u *user.User) ([]string, error)
+	UserLookup      func(username string) (*user.User, error)
+	UserLookupGroup func(name string) (*user.Group, error)
+	IoutilReadFile  func(filename string) ([]byte, error)
+	OsGetuid        func() int
+	OsGetgid        func() int
+	OsGetgroups     func() ([]int, error)
 	//custom
 }
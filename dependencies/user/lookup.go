@@ -0,0 +1,97 @@
+package user
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//file listing groups, consulted when looking for a group the current user is not a member of
+const GroupFile = "/etc/group"
+
+//will return uid of the user with given name
+func LookupUid(name string) (uid uint32, err error) {
+	u, err := impl.UserLookup(name)
+	if err != nil {
+		return
+	}
+	return parseId(u.Uid)
+}
+
+//will return gid of the group with given name
+func LookupGid(name string) (gid uint32, err error) {
+	g, err := impl.UserLookupGroup(name)
+	if err != nil {
+		return
+	}
+	return parseId(g.Gid)
+}
+
+//will return current user's gids other than primary gid, in the order reported by the user database
+func SupplementaryGids() (gids []uint32, err error) {
+	u, err := impl.UserCurrent()
+	if err != nil {
+		return
+	}
+
+	groupIds, err := impl.UserGroupIds(u)
+	if err != nil {
+		return
+	}
+
+	for _, groupId := range groupIds {
+		if groupId == u.Gid {
+			continue
+		}
+		gid, err := parseId(groupId)
+		if err != nil {
+			continue
+		}
+		gids = append(gids, gid)
+	}
+	return
+}
+
+//will return gid of a group (listed in GroupFile) the current user is not a member of, handy for negative tests
+func NonMemberGid() (gid uint32, err error) {
+	u, err := impl.UserCurrent()
+	if err != nil {
+		return
+	}
+
+	groupIds, err := impl.UserGroupIds(u)
+	if err != nil {
+		return
+	}
+
+	member := map[string]bool{u.Gid: true}
+	for _, groupId := range groupIds {
+		member[groupId] = true
+	}
+
+	contents, err := impl.IoutilReadFile(GroupFile)
+	if err != nil {
+		return
+	}
+
+	//each line is in the format of group_name:password:gid:user_list
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 3 || member[fields[2]] {
+			continue
+		}
+		if gid, err = parseId(fields[2]); err == nil {
+			return
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("no group the current user (uid %s) is not a member of found in %s", u.Uid, GroupFile))
+}
+
+func parseId(id string) (uint32, error) {
+	t, err := strconv.ParseUint(id, 10, 32)
+	return uint32(t), err
+}
@@ -0,0 +1,134 @@
+package user_test
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	osuser "os/user"
+	"github.com/outo/filefactory/dependencies/user"
+)
+
+var _ = Describe("pkg user lookup.go unit test", func() {
+
+	BeforeEach(func() {
+		user.ResetImplementation()
+		user.MockForTest(func(modifyThis *user.Implementation) {
+			modifyThis.UserCurrent = func() (*osuser.User, error) {
+				return &osuser.User{Uid: "1000", Gid: "100"}, nil
+			}
+			modifyThis.UserGroupIds = func(u *osuser.User) ([]string, error) {
+				return []string{"100", "27", "non-numeric", "4"}, nil
+			}
+			modifyThis.IoutilReadFile = func(filename string) ([]byte, error) {
+				return []byte("root:x:0:\nusers:x:100:\nsudo:x:27:me\nadm:x:4:me\nmalformed\nnogroup:x:65534:\n"), nil
+			}
+		})
+	})
+
+	AfterEach(func() {
+		user.ResetImplementation()
+	})
+
+	Describe("LookupUid", func() {
+		It("will return uid of the user with given name", func() {
+			user.MockForTest(func(modifyThis *user.Implementation) {
+				modifyThis.UserLookup = func(username string) (*osuser.User, error) {
+					Expect(username).To(Equal("nobody"))
+					return &osuser.User{Uid: "65534"}, nil
+				}
+			})
+			uid, err := user.LookupUid("nobody")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(uid).To(Equal(uint32(65534)))
+		})
+		It("will return user.Lookup error", func() {
+			expectedError := errors.New("user.Lookup error")
+			user.MockForTest(func(modifyThis *user.Implementation) {
+				modifyThis.UserLookup = func(username string) (*osuser.User, error) {
+					return nil, expectedError
+				}
+			})
+			_, err := user.LookupUid("nobody")
+			Expect(err).To(MatchError(expectedError))
+		})
+	})
+
+	Describe("LookupGid", func() {
+		It("will return gid of the group with given name", func() {
+			user.MockForTest(func(modifyThis *user.Implementation) {
+				modifyThis.UserLookupGroup = func(name string) (*osuser.Group, error) {
+					Expect(name).To(Equal("adm"))
+					return &osuser.Group{Gid: "4"}, nil
+				}
+			})
+			gid, err := user.LookupGid("adm")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gid).To(Equal(uint32(4)))
+		})
+		It("will return error if gid is non-numeric", func() {
+			user.MockForTest(func(modifyThis *user.Implementation) {
+				modifyThis.UserLookupGroup = func(name string) (*osuser.Group, error) {
+					return &osuser.Group{Gid: "non-numeric"}, nil
+				}
+			})
+			_, err := user.LookupGid("adm")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("SupplementaryGids", func() {
+		It("will return current user's numeric gids other than primary, in order", func() {
+			gids, err := user.SupplementaryGids()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gids).To(Equal([]uint32{27, 4}))
+		})
+		It("will return error if user groups cannot be retrieved", func() {
+			expectedError := errors.New("User.GroupIds error")
+			user.MockForTest(func(modifyThis *user.Implementation) {
+				modifyThis.UserGroupIds = func(u *osuser.User) ([]string, error) {
+					return nil, expectedError
+				}
+			})
+			_, err := user.SupplementaryGids()
+			Expect(err).To(MatchError(expectedError))
+		})
+	})
+
+	Describe("NonMemberGid", func() {
+		It("will return the first gid listed in group file that current user is not a member of", func() {
+			gid, err := user.NonMemberGid()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gid).To(Equal(uint32(0)))
+
+			user.MockForTest(func(modifyThis *user.Implementation) {
+				modifyThis.UserGroupIds = func(u *osuser.User) ([]string, error) {
+					return []string{"0", "27", "4"}, nil
+				}
+			})
+			gid, err = user.NonMemberGid()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gid).To(Equal(uint32(65534)))
+		})
+		It("will return error if there is no such group", func() {
+			user.MockForTest(func(modifyThis *user.Implementation) {
+				modifyThis.IoutilReadFile = func(filename string) ([]byte, error) {
+					return []byte("users:x:100:\n"), nil
+				}
+			})
+			_, err := user.NonMemberGid()
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no group the current user (uid 1000) is not a member of"))
+		})
+		It("will return error if group file cannot be read", func() {
+			expectedError := errors.New("ioutil.ReadFile error")
+			user.MockForTest(func(modifyThis *user.Implementation) {
+				modifyThis.IoutilReadFile = func(filename string) ([]byte, error) {
+					Expect(filename).To(Equal(user.GroupFile))
+					return nil, expectedError
+				}
+			})
+			_, err := user.NonMemberGid()
+			Expect(err).To(MatchError(expectedError))
+		})
+	})
+})
@@ -1,12 +1,37 @@
 package user
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 )
 
 // will return current user's uid, primary gid and an example of this user's other gid (same as primary gid if none available)
+// The ids come from the user database. If that fails (e.g. in a minimal container with no passwd entry for the current user)
+// they come from the process itself (os.Getuid, os.Getgid and os.Getgroups).
 func CurrentIds() (uid, primaryGid, otherGid uint32, err error) {
 
+	uid, primaryGid, gids, err := idsFromUserDatabase()
+	if err != nil {
+		var processErr error
+		uid, primaryGid, gids, processErr = idsFromProcess()
+		if processErr != nil {
+			err = errors.New(fmt.Sprintf("unable to retrieve current user ids from user database (%s) nor from the process (%s)", err, processErr))
+			return
+		}
+		err = nil
+	}
+
+	otherGid = primaryGid
+	for _, gid := range gids {
+		if gid != primaryGid {
+			otherGid = gid
+		}
+	}
+	return
+}
+
+func idsFromUserDatabase() (uid, primaryGid uint32, gids []uint32, err error) {
 	u, err := impl.UserCurrent()
 	if err != nil {
 		return
@@ -26,25 +51,28 @@ func CurrentIds() (uid, primaryGid, otherGid uint32, err error) {
 	}
 	primaryGid = uint32(t)
 
-	gids, err := impl.UserGroupIds(u)
+	groupIds, err := impl.UserGroupIds(u)
 	if err != nil {
 		return
 	}
 
-	for _, gid := range gids {
-		if gid == u.Gid {
-			continue
-		}
-		t, err = strconv.ParseUint(gid, 10, 32)
+	for _, groupId := range groupIds {
+		t, err := strconv.ParseUint(groupId, 10, 32)
 		if err != nil {
 			continue
 		}
-		otherGid = uint32(t)
+		gids = append(gids, uint32(t))
 	}
+	return
+}
 
-	if otherGid == 0 {
-		otherGid = primaryGid
+func idsFromProcess() (uid, primaryGid uint32, gids []uint32, err error) {
+	groups, err := impl.OsGetgroups()
+	if err != nil {
+		return
 	}
-
-	return
+	for _, group := range groups {
+		gids = append(gids, uint32(group))
+	}
+	return uint32(impl.OsGetuid()), uint32(impl.OsGetgid()), gids, nil
 }
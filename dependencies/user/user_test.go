@@ -21,49 +21,81 @@ var _ = Describe("pkg user unit test", func() {
 			}
 		}
 
-		It("will return error if user.Current fails", func() {
-			expectedError := errors.New("user.Current error")
-			user.MockForTest(func(modifyThis *user.Implementation) {
-				modifyThis.UserCurrent = func() (*osuser.User, error) {
-					return nil, expectedError
-				}
+		Context("given user database lookup fails", func() {
+			BeforeEach(func() {
+				user.MockForTest(func(modifyThis *user.Implementation) {
+					modifyThis.OsGetuid = func() int { return 1001 }
+					modifyThis.OsGetgid = func() int { return 101 }
+					modifyThis.OsGetgroups = func() ([]int, error) { return []int{101, 7}, nil }
+				})
+			})
+			AfterEach(func() {
+				user.ResetImplementation()
 			})
-			_, _, _, err := user.CurrentIds()
-			Expect(err).To(Equal(expectedError))
-		})
 
-		It("will return error if uid is non-numeric", func() {
-			user.MockForTest(func(modifyThis *user.Implementation) {
-				modifyThis.UserCurrent = mockUserCurrent("non-numeric", "", nil)
+			expectIdsOfTheProcess := func() {
+				uid, primaryGid, otherGid, err := user.CurrentIds()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(uid).To(Equal(uint32(1001)))
+				Expect(primaryGid).To(Equal(uint32(101)))
+				Expect(otherGid).To(Equal(uint32(7)))
+			}
+
+			It("will fall back to ids of the process if user.Current fails", func() {
+				user.MockForTest(func(modifyThis *user.Implementation) {
+					modifyThis.UserCurrent = func() (*osuser.User, error) {
+						return nil, errors.New("user.Current error")
+					}
+				})
+				expectIdsOfTheProcess()
 			})
 
-			_, _, _, err := user.CurrentIds()
-			Expect(err).Should(HaveOccurred())
-		})
+			It("will fall back to ids of the process if uid is non-numeric", func() {
+				user.MockForTest(func(modifyThis *user.Implementation) {
+					modifyThis.UserCurrent = mockUserCurrent("non-numeric", "", nil)
+				})
+				expectIdsOfTheProcess()
+			})
 
-		It("will return error if gid is non-numeric", func() {
-			user.MockForTest(func(modifyThis *user.Implementation) {
-				modifyThis.UserCurrent = mockUserCurrent("4", "non-numeric", nil)
+			It("will fall back to ids of the process if gid is non-numeric", func() {
+				user.MockForTest(func(modifyThis *user.Implementation) {
+					modifyThis.UserCurrent = mockUserCurrent("4", "non-numeric", nil)
+				})
+				expectIdsOfTheProcess()
 			})
 
-			_, _, _, err := user.CurrentIds()
-			Expect(err).Should(HaveOccurred())
-		})
+			It("will fall back to ids of the process if user groups cannot be retrieved", func() {
+				user.MockForTest(func(modifyThis *user.Implementation) {
+					modifyThis.UserCurrent = mockUserCurrent("4", "4", nil)
+					modifyThis.UserGroupIds = func(u *osuser.User) ([]string, error) {
+						return nil, errors.New("User.GroupsIds error")
+					}
+				})
+				expectIdsOfTheProcess()
+			})
 
-		It("will return error if user groups cannot be retrieved", func() {
-			expectedError := errors.New("User.GroupsIds error")
-			user.MockForTest(func(modifyThis *user.Implementation) {
-				modifyThis.UserCurrent = mockUserCurrent("4", "4", nil)
-				modifyThis.UserGroupIds = func(u *osuser.User) ([]string, error) {
-					return nil, expectedError
-				}
-				_, _, _, err := user.CurrentIds()
-				Expect(err).To(Equal(expectedError))
+			It("will return primary gid as other gid, if the process has no other gid", func() {
+				user.MockForTest(func(modifyThis *user.Implementation) {
+					modifyThis.UserCurrent = mockUserCurrent("non-numeric", "", nil)
+					modifyThis.OsGetgroups = func() ([]int, error) { return nil, nil }
+				})
+				_, primaryGid, otherGid, err := user.CurrentIds()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(otherGid).To(Equal(primaryGid))
 			})
 
-			_, _, _, err := user.CurrentIds()
-			Expect(err).Should(HaveOccurred())
+			It("will return error if process groups cannot be retrieved either", func() {
+				user.MockForTest(func(modifyThis *user.Implementation) {
+					modifyThis.UserCurrent = func() (*osuser.User, error) {
+						return nil, errors.New("user.Current error")
+					}
+					modifyThis.OsGetgroups = func() ([]int, error) { return nil, errors.New("os.Getgroups error") }
+				})
+				_, _, _, err := user.CurrentIds()
+				Expect(err).To(MatchError("unable to retrieve current user ids from user database (user.Current error) nor from the process (os.Getgroups error)"))
+			})
 		})
+
 		It("will return primary gid as other gid, if there isn't other gid", func() {
 			user.MockForTest(func(modifyThis *user.Implementation) {
 				modifyThis.UserCurrent = mockUserCurrent("3424", "4", nil)
@@ -1,5 +1,13 @@
 package diff
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
 type FileDifference uint64
 
 const (
@@ -14,4 +22,120 @@ const (
 	Size
 	LinkTarget
 	Contents
+	ChangeTime
+	BirthTime
 )
+
+//rendered in place of the zero value
+const NoneName = "None"
+
+var (
+	registryMutex sync.RWMutex
+	//names of the differences, built-in ones and the ones allocated with Register
+	names = map[FileDifference]string{
+		All:                       "All",
+		NotPresentOrNotAccessible: "NotPresentOrNotAccessible",
+		ModeType:                  "ModeType",
+		ModePerm:                  "ModePerm",
+		Owner:                     "Owner",
+		Group:                     "Group",
+		ModTime:                   "ModTime",
+		AccTime:                   "AccTime",
+		Size:                      "Size",
+		LinkTarget:                "LinkTarget",
+		Contents:                  "Contents",
+		ChangeTime:                "ChangeTime",
+		BirthTime:                 "BirthTime",
+	}
+	lastAllocated = BirthTime
+)
+
+//Allocates a new difference bit for custom definitions, so that it does not collide with the built-in ones
+// (or the ones registered by someone else). Typically invoked when initialising package level variable.
+//Will panic if the name is already taken (names are case insensitive, as in Parse) or there are no more bits available.
+func Register(name string) FileDifference {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if name == "" || strings.EqualFold(name, NoneName) || strings.ContainsAny(name, "|, ") || hasHexPrefix(name) {
+		panic(fmt.Sprintf("invalid difference name %q", name))
+	}
+	for _, existing := range names {
+		if strings.EqualFold(existing, name) {
+			panic(fmt.Sprintf("difference %q is already registered", name))
+		}
+	}
+	if lastAllocated<<1 == 0 {
+		panic(fmt.Sprintf("unable to register difference %q, all bits are allocated", name))
+	}
+	lastAllocated <<= 1
+	names[lastAllocated] = name
+	return lastAllocated
+}
+
+//each of the set bits, from the least significant one
+func (d FileDifference) Bits() (bits []FileDifference) {
+	for bit := FileDifference(1); bit != 0; bit <<= 1 {
+		if d&bit != 0 {
+			bits = append(bits, bit)
+		}
+	}
+	return
+}
+
+//names of the set bits joined with |, e.g. ModePerm|ModTime, unregistered bits are rendered in hex
+func (d FileDifference) String() string {
+	if d == 0 {
+		return NoneName
+	}
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	rendered := []string{}
+	for _, bit := range d.Bits() {
+		if name, ok := names[bit]; ok {
+			rendered = append(rendered, name)
+		} else {
+			rendered = append(rendered, fmt.Sprintf("0x%x", uint64(bit)))
+		}
+	}
+	return strings.Join(rendered, "|")
+}
+
+func hasHexPrefix(s string) bool {
+	return strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")
+}
+
+//Inverse of String, names (case insensitive) or hex values separated with | or comma, e.g. "ModePerm|modtime".
+//Empty string or None is zero value.
+func Parse(s string) (d FileDifference, err error) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '|' || r == ',' }) {
+		part = strings.TrimSpace(part)
+		if part == "" || strings.EqualFold(part, NoneName) {
+			continue
+		}
+		if hasHexPrefix(part) {
+			value, err := strconv.ParseUint(part, 0, 64)
+			if err != nil {
+				return 0, errors.New(fmt.Sprintf("invalid difference %q: %s", part, err))
+			}
+			d |= FileDifference(value)
+			continue
+		}
+		found := false
+		for bit, name := range names {
+			if strings.EqualFold(name, part) {
+				d |= bit
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, errors.New(fmt.Sprintf("unknown difference %q", part))
+		}
+	}
+	return
+}
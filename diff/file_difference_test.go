@@ -0,0 +1,87 @@
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"fmt"
+	"github.com/outo/filefactory/diff"
+)
+
+var _ = Describe("pkg diff file_difference.go unit test", func() {
+
+	It("will render names of the set bits", func() {
+		Expect(diff.ModePerm.String()).To(Equal("ModePerm"))
+		Expect((diff.ModTime | diff.ModePerm).String()).To(Equal("ModePerm|ModTime"))
+		Expect(fmt.Sprint(diff.Owner | diff.Group | diff.Contents)).To(Equal("Owner|Group|Contents"))
+	})
+
+	It("will render zero value as None and unregistered bits in hex", func() {
+		Expect(diff.FileDifference(0).String()).To(Equal("None"))
+		Expect((diff.Size | diff.FileDifference(1<<62)).String()).To(Equal("Size|0x4000000000000000"))
+	})
+
+	It("will iterate over set bits, from the least significant one", func() {
+		Expect((diff.AccTime | diff.ModeType | diff.LinkTarget).Bits()).To(Equal([]diff.FileDifference{diff.ModeType, diff.AccTime, diff.LinkTarget}))
+		Expect(diff.FileDifference(0).Bits()).To(BeEmpty())
+	})
+
+	Describe("parsing", func() {
+		It("will parse what String renders", func() {
+			for _, d := range []diff.FileDifference{diff.ModePerm, diff.ModTime | diff.ModePerm, diff.BirthTime | diff.All, 0} {
+				parsed, err := diff.Parse(d.String())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(parsed).To(Equal(d))
+			}
+		})
+		It("will accept names regardless of their case, separated with comma or |, as well as hex values", func() {
+			parsed, err := diff.Parse("modeperm, MODTIME|0x10|0X20")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed).To(Equal(diff.ModePerm | diff.ModTime | diff.Owner | diff.Group))
+		})
+		It("will parse empty string as zero value", func() {
+			parsed, err := diff.Parse("")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed).To(BeZero())
+		})
+		It("will return error for unknown names", func() {
+			_, err := diff.Parse("ModePerm|Colour")
+			Expect(err).To(MatchError(`unknown difference "Colour"`))
+		})
+		It("will return error for invalid hex values", func() {
+			_, err := diff.Parse("0xZZ")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("registering custom differences", func() {
+		It("will allocate a bit not colliding with the other differences, usable by name", func() {
+			deviceNumber := diff.Register("DeviceNumber")
+			Expect(deviceNumber.Bits()).To(HaveLen(1))
+			Expect(deviceNumber & (diff.All | diff.NotPresentOrNotAccessible | diff.ModeType | diff.ModePerm | diff.Owner | diff.Group |
+				diff.ModTime | diff.AccTime | diff.Size | diff.LinkTarget | diff.Contents | diff.ChangeTime | diff.BirthTime)).To(BeZero())
+
+			another := diff.Register("Xattr")
+			Expect(another & deviceNumber).To(BeZero())
+
+			Expect((deviceNumber | diff.Size).String()).To(Equal("Size|DeviceNumber"))
+			parsed, err := diff.Parse("xattr|DeviceNumber")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed).To(Equal(deviceNumber | another))
+		})
+		It("will panic if the name is taken", func() {
+			Expect(func() { diff.Register("ModePerm") }).To(Panic())
+		})
+		It("will panic if the name differs from a taken one only in case, as Parse would not tell them apart", func() {
+			Expect(func() { diff.Register("modeperm") }).To(Panic())
+			Expect(func() { diff.Register("SIZE") }).To(Panic())
+		})
+		It("will panic if the name can't be parsed back", func() {
+			Expect(func() { diff.Register("") }).To(Panic())
+			Expect(func() { diff.Register("a|b") }).To(Panic())
+			Expect(func() { diff.Register("a,b") }).To(Panic())
+			Expect(func() { diff.Register("None") }).To(Panic())
+			Expect(func() { diff.Register("none") }).To(Panic())
+			Expect(func() { diff.Register("0Xff") }).To(Panic())
+		})
+	})
+})
@@ -8,12 +8,15 @@ import (
 	"path/filepath"
 	"time"
 	"os"
+	"syscall"
 	"github.com/outo/filefactory"
 	"github.com/outo/filefactory/def"
 	"github.com/outo/filefactory/attr"
 	"github.com/outo/filefactory/file"
 	"github.com/outo/filefactory/verify"
 	"github.com/outo/filefactory/diff"
+	"github.com/outo/filefactory/match"
+	"github.com/outo/filefactory/testingaids/userns"
 )
 
 var _ = Describe("examples", func() {
@@ -146,9 +149,9 @@ var _ = Describe("examples", func() {
 
 				//or
 				Expect(verErr.Errors).To(ConsistOf(
-					verify.Error{FileDifference: diff.NotPresentOrNotAccessible, Path: filepath.Join(tempRootDir, "relative/path/to/regular-file"), Err: errors.New("file does not exist")},
-					verify.Error{FileDifference: diff.NotPresentOrNotAccessible, Path: filepath.Join(tempRootDir, "relative/path/to/directory"), Err: errors.New("file does not exist")},
-					verify.Error{FileDifference: diff.NotPresentOrNotAccessible, Path: filepath.Join(tempRootDir, "relative/path/to/symlink"), Err: errors.New("file does not exist")},
+					verify.Error{FileDifference: diff.NotPresentOrNotAccessible, Path: filepath.Join(tempRootDir, "relative/path/to/regular-file"), RelPath: "relative/path/to/regular-file", Err: errors.New("file does not exist")},
+					verify.Error{FileDifference: diff.NotPresentOrNotAccessible, Path: filepath.Join(tempRootDir, "relative/path/to/directory"), RelPath: "relative/path/to/directory", Err: errors.New("file does not exist")},
+					verify.Error{FileDifference: diff.NotPresentOrNotAccessible, Path: filepath.Join(tempRootDir, "relative/path/to/symlink"), RelPath: "relative/path/to/symlink", Err: errors.New("file does not exist")},
 				))
 			})
 
@@ -244,6 +247,136 @@ var _ = Describe("examples", func() {
 			Expect(verErr.HasDifference(diff.ModePerm, abs("relative/path/to/directory")))
 		})
 
+		Specify("verify contents of a generated file loosely, using content matchers", func() {
+
+			//pretend the code under test produced this config
+			err := os.MkdirAll(abs("generated"), 0777)
+			Expect(err).ShouldNot(HaveOccurred())
+			err = ioutil.WriteFile(abs("generated/config.json"), []byte("{\n  \"port\": 8080,\n  \"debug\": false\n}\n"), 0666)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			//only the contents matter here, size is not verified when matchers are attached
+			filesToExpect := fileFactory.FilesToExpect(
+				def.Reg("generated/config.json",
+					verify.AllByDefault(false),
+					verify.Contents(true),
+					match.Contains(`"port"`),
+					match.Regex(`"port": \d+`),
+					match.LineCount(4),
+					match.JSONEqual(`{"debug":false,"port":8080}`)),
+			)
+			err = filefactory.VerifyFiles(tempRootDir, filesToExpect...)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			filesToExpect = fileFactory.FilesToExpect(
+				def.Reg("generated/config.json",
+					verify.AllByDefault(false),
+					verify.Contents(true),
+					match.JSONEqual(`{"debug":true,"port":8080}`)),
+			)
+			err = filefactory.VerifyFiles(tempRootDir, filesToExpect...)
+			Expect(err).Should(HaveOccurred())
+			verErr := err.(*verify.Errors)
+			Expect(verErr.DifferenceFor(abs("generated/config.json"))).To(Equal(diff.Contents))
+		})
+
+		Specify("verify that reading a file does not touch its metadata, using change time (ctime) snapshot", func() {
+			filesToCreate := fileFactory.FilesToCreate(
+				def.Reg("read-only-input"),
+			)
+			err := filefactory.CreateFiles(tempRootDir, filesToCreate...)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			snapshot, err := file.NewFromPath(abs("read-only-input"))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			//code under test would only read the file here
+			_, err = ioutil.ReadFile(abs("read-only-input"))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			filesToExpect := fileFactory.FilesToExpect(
+				def.Reg("read-only-input", attr.ChangedTime(snapshot.Changed)),
+			)
+			err = filefactory.VerifyFiles(tempRootDir, filesToExpect...)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			//whereas changing its metadata, even to the same value, will be noticed
+			time.Sleep(10 * time.Millisecond)
+			err = os.Chmod(abs("read-only-input"), snapshot.Mode)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = filefactory.VerifyFiles(tempRootDir, filesToExpect...)
+			Expect(err).Should(HaveOccurred())
+			verErr := err.(*verify.Errors)
+			Expect(verErr.DifferenceFor(abs("read-only-input"))).To(Equal(diff.ChangeTime))
+		})
+
+		Specify("ownership can be aligned and verified without sudo, inside an unprivileged user namespace", func() {
+			if !userns.Inside() {
+				if reason := userns.Unavailable(); reason != "" {
+					Skip(reason)
+				}
+				//re-execute this very spec in user namespace, where current user is root
+				output, err := userns.Run(CurrentGinkgoTestDescription().FullTestText)
+				Expect(err).ShouldNot(HaveOccurred(), string(output))
+				return
+			}
+
+			//0 is always mapped to the user namespace (it is the current user outside of it), other ids may not be
+			filesToCreate := fileFactory.FilesToCreate(
+				def.Reg("owned-by-root", attr.ArbitraryUid(0), attr.ArbitraryGid(0)),
+			)
+			err := filefactory.CreateFiles(tempRootDir, filesToCreate...)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = filefactory.VerifyFiles(tempRootDir, filesToCreate...)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			filesToExpect := fileFactory.FilesToExpect(
+				def.Reg("owned-by-root", attr.ArbitraryUid(1), attr.ArbitraryGid(1)),
+			)
+			err = filefactory.VerifyFiles(tempRootDir, filesToExpect...)
+			Expect(err).Should(HaveOccurred())
+			verErr := err.(*verify.Errors)
+			Expect(verErr.DifferenceFor(abs("owned-by-root"))).To(Equal(diff.Owner | diff.Group))
+		})
+
+		Specify("ownership other than root can be aligned and verified, if user namespace maps a range of ids", func() {
+			if !userns.Inside() {
+				if reason := userns.Unavailable(); reason != "" {
+					Skip(reason)
+				}
+				output, err := userns.Run(CurrentGinkgoTestDescription().FullTestText)
+				Expect(err).ShouldNot(HaveOccurred(), string(output))
+				return
+			}
+			if userns.Mapped() <= 1000 {
+				Skip("ids up to 1000 are not mapped to the user namespace (no subordinate ids of the current user)")
+			}
+
+			filesToCreate := fileFactory.FilesToCreate(
+				def.Reg("owned-by-someone", attr.ArbitraryUid(1000), attr.ArbitraryGid(1000)),
+			)
+			err := filefactory.CreateFiles(tempRootDir, filesToCreate...)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			err = filefactory.VerifyFiles(tempRootDir, filesToCreate...)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fileInfo, err := os.Lstat(abs("owned-by-someone"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fileInfo.Sys().(*syscall.Stat_t).Uid).To(BeEquivalentTo(1000))
+			Expect(fileInfo.Sys().(*syscall.Stat_t).Gid).To(BeEquivalentTo(1000))
+
+			filesToExpect := fileFactory.FilesToExpect(
+				def.Reg("owned-by-someone", attr.ArbitraryUid(0), attr.ArbitraryGid(0)),
+			)
+			err = filefactory.VerifyFiles(tempRootDir, filesToExpect...)
+			Expect(err).Should(HaveOccurred())
+			verErr := err.(*verify.Errors)
+			Expect(verErr.DifferenceFor(abs("owned-by-someone"))).To(Equal(diff.Owner | diff.Group))
+		})
+
 		Specify("not verifying mode permissions does not mean the mode type can be incompatible", func() {
 			fileFactory = filefactory.New(verify.ModePerm(false))
 
@@ -1,7 +1,9 @@
 package file
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 	"github.com/outo/filefactory/dependencies/path"
 )
@@ -15,13 +17,19 @@ func init() {
 func getProductionImplementation() Implementation {
 	return Implementation{
 		//built-in
-		OsChmod:   os.Chmod,
-		OsChtimes: os.Chtimes,
-		OsLchown:  os.Lchown,
-		OsLstat:   os.Lstat,
+		OsChmod:              os.Chmod,
+		OsChtimes:            os.Chtimes,
+		OsLchown:             os.Lchown,
+		OsLstat:              os.Lstat,
+		OsRemove:             os.Remove,
+		IoutilTempFile:       ioutil.TempFile,
+		TimeNow:              time.Now,
+		FilepathEvalSymlinks: filepath.EvalSymlinks,
 		//custom
 		WrapNewFromPath: NewFromPath,
+		BirthTime:       birthTime,
 		PathExists:      path.Exists,
+		Disk:            OsDisk{},
 	}
 }
 
@@ -36,11 +44,17 @@ func ResetImplementation() {
 
 type Implementation struct {
 	//builtin
-	OsChmod   func(name string, mode os.FileMode) error
-	OsChtimes func(name string, atime time.Time, mtime time.Time) error
-	OsLchown  func(name string, uid int, gid int) error
-	OsLstat   func(name string) (os.FileInfo, error)
+	OsChmod              func(name string, mode os.FileMode) error
+	OsChtimes            func(name string, atime time.Time, mtime time.Time) error
+	OsLchown             func(name string, uid int, gid int) error
+	OsLstat              func(name string) (os.FileInfo, error)
+	OsRemove             func(name string) error
+	IoutilTempFile       func(dir, pattern string) (f *os.File, err error)
+	TimeNow              func() time.Time
+	FilepathEvalSymlinks func(path string) (string, error)
 	//custom
 	WrapNewFromPath func(path string) (meta Meta, err error)
+	BirthTime       func(path string) (born time.Time, err error)
 	PathExists      func(path string) (exists bool, err error)
+	Disk            Disk
 }
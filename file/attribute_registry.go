@@ -0,0 +1,195 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/diff"
+	"github.com/outo/filefactory/verify"
+)
+
+//Behaviour of a custom attribute type, see RegisterAttribute. Each of the functions is optional.
+type AttributeHandler struct {
+	//name of the verification aspect, verification of this attribute can be turned off with verify.NewInstruction(false, Aspect)
+	Aspect string
+	//reported when the actual value differs from the expected one, allocate it with diff.Register
+	Difference diff.FileDifference
+	//invoked with the path of a file (or symlink) just created
+	Create func(path string, value interface{}) error
+	//invoked when attributes of a file are aligned, for symlinks the path is not followed
+	Align func(path string, value interface{}) error
+	//retrieves the actual value of the attribute, which is compared with the expected one using reflect.DeepEqual
+	Verify func(path string, expected interface{}) (actual interface{}, err error)
+}
+
+type registeredAttribute struct {
+	AttributeHandler
+	//built-in attributes are set on Meta fields, custom ones are kept in Meta.CustomAttributes
+	populate func(m *Meta, value interface{})
+}
+
+var (
+	attributesMutex sync.RWMutex
+	attributes      = map[reflect.Type]registeredAttribute{}
+)
+
+func init() {
+	builtin := func(sample interface{}, populate func(m *Meta, value interface{})) {
+		attributes[reflect.TypeOf(sample)] = registeredAttribute{populate: populate}
+	}
+	builtin(os.FileMode(0), func(m *Meta, value interface{}) { m.Mode = value.(os.FileMode) })
+	builtin(attr.AccessedTime{}, func(m *Meta, value interface{}) { m.Accessed = time.Time(value.(attr.AccessedTime)) })
+	builtin(attr.ModifiedTime{}, func(m *Meta, value interface{}) { m.Modified = time.Time(value.(attr.ModifiedTime)) })
+	builtin(attr.ChangedTime{}, func(m *Meta, value interface{}) { m.Changed = time.Time(value.(attr.ChangedTime)) })
+	builtin(attr.BirthTime{}, func(m *Meta, value interface{}) { m.Born = time.Time(value.(attr.BirthTime)) })
+	builtin(attr.Uid(0), func(m *Meta, value interface{}) { m.Uid = uint32(value.(attr.Uid)) })
+	builtin(attr.Gid(0), func(m *Meta, value interface{}) { m.Gid = uint32(value.(attr.Gid)) })
+	builtin(attr.TimePrecision(0), func(m *Meta, value interface{}) { m.TimePrecision = time.Duration(value.(attr.TimePrecision)) })
+	builtin(attr.TimeConstraint{}, func(m *Meta, value interface{}) {
+		constraint := value.(attr.TimeConstraint)
+		if err := escapesLexically(constraint.NewerThan); err != nil {
+			m.problems = append(m.problems, err)
+			return
+		}
+		m.TimeConstraints = append(m.TimeConstraints, constraint)
+	})
+	builtin(verify.Instruction{}, func(m *Meta, value interface{}) {
+		m.VerificationInstructions = append(m.VerificationInstructions, value.(verify.Instruction))
+	})
+}
+
+//Makes attribute type of the sample recognised by Meta.Populate and so by all the definitions embedding Meta.
+//Typically invoked when initialising package level variable.
+//Will panic if the type or the aspect is already registered, or the aspect name is missing.
+func RegisterAttribute(sample interface{}, handler AttributeHandler) {
+	attributesMutex.Lock()
+	defer attributesMutex.Unlock()
+
+	if sample == nil {
+		panic("unable to register attribute of nil type")
+	}
+	if _, isResolver := sample.(attr.Resolver); isResolver {
+		panic(fmt.Sprintf("unable to register attribute type %T, it is a resolver", sample))
+	}
+	attributeType := reflect.TypeOf(sample)
+	if _, registered := attributes[attributeType]; registered {
+		panic(fmt.Sprintf("attribute type %s is already registered", attributeType))
+	}
+	if handler.Aspect == "" {
+		panic(fmt.Sprintf("attribute type %s has to have an aspect name", attributeType))
+	}
+	for _, builtinAspect := range []verify.Instruction{
+		verify.AllByDefault(true), verify.ModePerm(true), verify.ModifiedTime(true), verify.AccessedTime(true),
+		verify.Uid(true), verify.Gid(true), verify.Size(true), verify.SymlinkTarget(true), verify.Contents(true),
+		verify.ChangedTime(true), verify.BirthTime(true),
+	} {
+		if builtinAspect.Aspect == handler.Aspect {
+			panic(fmt.Sprintf("aspect %q is already registered", handler.Aspect))
+		}
+	}
+	for _, existing := range attributes {
+		if existing.Aspect == handler.Aspect {
+			panic(fmt.Sprintf("aspect %q is already registered", handler.Aspect))
+		}
+	}
+	if handler.Verify != nil && handler.Difference == 0 {
+		panic(fmt.Sprintf("attribute type %s has to have a difference to be verified", attributeType))
+	}
+
+	attributes[attributeType] = registeredAttribute{AttributeHandler: handler}
+}
+
+//Reverts RegisterAttribute of the sample's type, e.g. for a test registering an attribute temporarily.
+//Will panic if the type is built-in, does nothing if it isn't registered.
+func UnregisterAttribute(sample interface{}) {
+	attributesMutex.Lock()
+	defer attributesMutex.Unlock()
+
+	attributeType := reflect.TypeOf(sample)
+	if registered, ok := attributes[attributeType]; ok && registered.populate != nil {
+		panic(fmt.Sprintf("unable to unregister built-in attribute type %s", attributeType))
+	}
+	delete(attributes, attributeType)
+}
+
+func registeredAttributeOf(attribute interface{}) (registered registeredAttribute, ok bool) {
+	if attribute == nil {
+		return
+	}
+	attributesMutex.RLock()
+	defer attributesMutex.RUnlock()
+	registered, ok = attributes[reflect.TypeOf(attribute)]
+	return
+}
+
+//handlers of the custom attributes in the order they were populated
+func (m Meta) customHandlers() (handlers []AttributeHandler) {
+	for _, value := range m.CustomAttributes {
+		registered, _ := registeredAttributeOf(value)
+		handlers = append(handlers, registered.AttributeHandler)
+	}
+	return
+}
+
+//the last value of given custom attribute type wins, as with built-in ones
+func (m *Meta) setCustomAttribute(value interface{}) {
+	for i, existing := range m.CustomAttributes {
+		if reflect.TypeOf(existing) == reflect.TypeOf(value) {
+			m.CustomAttributes[i] = value
+			return
+		}
+	}
+	m.CustomAttributes = append(m.CustomAttributes, value)
+}
+
+//Will apply custom attributes to the file just created at path (already joined with root).
+//Invoked by Create of the definitions.
+func (m Meta) ApplyOnCreate(path string) (err error) {
+	return m.ApplyOnCreateOn(impl.Disk, path)
+}
+
+//As ApplyOnCreate, the handlers are invoked through disk
+func (m Meta) ApplyOnCreateOn(disk Disk, path string) (err error) {
+	for i, handler := range m.customHandlers() {
+		if handler.Create == nil {
+			continue
+		}
+		if err = disk.CreateAttribute(handler, path, m.CustomAttributes[i]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m Meta) alignCustomAttributes(disk Disk, path string) (err error) {
+	for i, handler := range m.customHandlers() {
+		if handler.Align == nil {
+			continue
+		}
+		if err = disk.AlignAttribute(handler, path, m.CustomAttributes[i]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (m Meta) verifyCustomAttributes(verr *verify.Errors, path string) (err error) {
+	for i, handler := range m.customHandlers() {
+		if handler.Verify == nil || !m.Should(verify.NewInstruction(true, handler.Aspect)) {
+			continue
+		}
+		expected := m.CustomAttributes[i]
+		actual, err := handler.Verify(path, expected)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			verr.AddError(verify.NewDifference(handler.Difference, path, m.Path, expected, actual, errors.New(fmt.Sprintf("expected %v, actual %v", expected, actual))))
+		}
+	}
+	return
+}
@@ -0,0 +1,134 @@
+package file_test
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/diff"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/verify"
+)
+
+//custom attribute used across the tests below, it is kept in memory rather than on the filesystem
+type label string
+
+var (
+	labelDifference = diff.Register("Label")
+	labels          map[string]label
+	labelCreated    []string
+)
+
+var _ = Describe("pkg file attribute_registry.go unit test", func() {
+
+	var tempDir string
+
+	BeforeEach(func() {
+		file.ResetImplementation()
+		var err error
+		tempDir, err = ioutil.TempDir("", "attribute-registry-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(os.Mkdir(filepath.Join(tempDir, "dir"), 0700)).To(Succeed())
+		labels, labelCreated = map[string]label{}, nil
+		//registry is global, each of the tests registers its attributes and unregisters them afterwards
+		file.RegisterAttribute(label(""), file.AttributeHandler{
+			Aspect:     "label",
+			Difference: labelDifference,
+			Create: func(path string, value interface{}) error {
+				labelCreated = append(labelCreated, path)
+				return nil
+			},
+			Align: func(path string, value interface{}) error {
+				labels[path] = value.(label)
+				return nil
+			},
+			Verify: func(path string, expected interface{}) (actual interface{}, err error) {
+				return labels[path], nil
+			},
+		})
+	})
+
+	AfterEach(func() {
+		file.ResetImplementation()
+		file.UnregisterAttribute(label(""))
+		os.RemoveAll(tempDir)
+	})
+
+	It("will make Populate recognise registered attribute type, the last value wins", func() {
+		m := file.Meta{}
+		Expect(file.IsMetaAttribute(label("a"))).To(BeTrue())
+		m.Populate("dir", label("a"), attr.Uid(3), label("b"))
+		Expect(m.CustomAttributes).To(Equal([]interface{}{label("b")}))
+		Expect(m.Uid).To(Equal(uint32(3)))
+	})
+
+	It("will apply the attribute on create, align it and verify it", func() {
+		m := file.Meta{}
+		m.Populate("dir", os.ModeDir|0700, label("expected"), verify.AllByDefault(false), verify.NewInstruction(true, "label"))
+
+		Expect(m.ApplyOnCreate(filepath.Join(tempDir, "dir"))).To(Succeed())
+		Expect(labelCreated).To(Equal([]string{filepath.Join(tempDir, "dir")}))
+
+		Expect(m.AlignAttributes(false, false, false, tempDir)).To(Succeed())
+		Expect(m.Verify(tempDir)).To(Succeed())
+
+		different := file.Meta{}
+		different.Populate("dir", os.ModeDir|0700, label("different"), verify.AllByDefault(false), verify.NewInstruction(true, "label"))
+		err := different.Verify(tempDir)
+		Expect(err).To(BeAssignableToTypeOf(&verify.Errors{}))
+		verr := err.(*verify.Errors)
+		Expect(verr.Errors).To(HaveLen(1))
+		Expect(verr.Errors[0].FileDifference).To(Equal(labelDifference))
+		Expect(verr.Errors[0].Expected).To(Equal(label("different")))
+		Expect(verr.Errors[0].Actual).To(Equal(label("expected")))
+		Expect(verr.Errors[0].Err).To(MatchError("expected different, actual expected"))
+
+		different.VerificationInstructions = append(different.VerificationInstructions, verify.NewInstruction(false, "label"))
+		Expect(different.Verify(tempDir)).To(Succeed())
+	})
+
+	It("will return error of the handler", func() {
+		type failing int
+		expectedError := errors.New("align error")
+		file.RegisterAttribute(failing(0), file.AttributeHandler{
+			Aspect: "failing",
+			Align:  func(path string, value interface{}) error { return expectedError },
+		})
+		defer file.UnregisterAttribute(failing(0))
+		m := file.Meta{}
+		m.Populate("dir", failing(1))
+		Expect(m.AlignAttributes(false, false, false, tempDir)).To(MatchError(expectedError))
+	})
+
+	It("will panic upon invalid registration", func() {
+		type unregistered int
+		Expect(func() { file.RegisterAttribute(nil, file.AttributeHandler{Aspect: "nil"}) }).To(Panic())
+		Expect(func() { file.RegisterAttribute(attr.CurrentUid(), file.AttributeHandler{Aspect: "resolver"}) }).To(Panic())
+		Expect(func() { file.RegisterAttribute(label(""), file.AttributeHandler{Aspect: "another-label"}) }).To(Panic())
+		Expect(func() { file.RegisterAttribute(attr.Uid(0), file.AttributeHandler{Aspect: "another-uid"}) }).To(Panic())
+		Expect(func() { file.RegisterAttribute(unregistered(0), file.AttributeHandler{}) }).To(Panic())
+		Expect(func() { file.RegisterAttribute(unregistered(0), file.AttributeHandler{Aspect: "uid"}) }).To(Panic())
+		Expect(func() { file.RegisterAttribute(unregistered(0), file.AttributeHandler{Aspect: "label"}) }).To(Panic())
+		Expect(func() {
+			file.RegisterAttribute(unregistered(0), file.AttributeHandler{
+				Aspect: "unregistered",
+				Verify: func(path string, expected interface{}) (interface{}, error) { return nil, nil },
+			})
+		}).To(Panic())
+		Expect(file.IsMetaAttribute(unregistered(0))).To(BeFalse())
+	})
+
+	It("will unregister the attribute type, but not a built-in one", func() {
+		file.UnregisterAttribute(label(""))
+		Expect(file.IsMetaAttribute(label("a"))).To(BeFalse())
+		m := file.Meta{}
+		m.Populate("dir", label("a"))
+		Expect(m.CustomAttributes).To(BeEmpty())
+
+		Expect(func() { file.UnregisterAttribute(attr.Uid(0)) }).To(Panic())
+		Expect(file.IsMetaAttribute(attr.Uid(0))).To(BeTrue())
+	})
+})
@@ -0,0 +1,180 @@
+// +build linux
+
+package file
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+//openat2 may ask to retry if the tree was renamed concurrently
+const openBeneathAttempts = 16
+
+func openRoot(root string) (int, error) {
+	fd, err := unix.Open(root, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return -1, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+	return fd, nil
+}
+
+//opens relPath under directory rootFd, neither .. nor symlinks (absolute or relative) can lead outside of it
+func openBeneath(rootFd int, relPath string, flags int, perm os.FileMode) (fd int, err error) {
+	how := &unix.OpenHow{
+		Flags:   uint64(flags | unix.O_CLOEXEC),
+		Resolve: unix.RESOLVE_BENEATH | unix.RESOLVE_NO_MAGICLINKS,
+	}
+	if flags&unix.O_CREAT != 0 {
+		how.Mode = uint64(perm.Perm())
+	}
+	for attempt := 0; attempt < openBeneathAttempts; attempt++ {
+		fd, err = unix.Openat2(rootFd, relPath, how)
+		if err != unix.EINTR && err != unix.EAGAIN {
+			return
+		}
+	}
+	return
+}
+
+func beneathError(op, root, relPath string, err error) error {
+	if err == unix.EXDEV {
+		return &PathEscapeError{Root: root, RelPath: relPath, Reason: "path resolves outside of root"}
+	}
+	return joinedPathError(op, root, relPath, err)
+}
+
+//Invokes operation with file descriptor of relPath under root, opened with flags.
+//If openat2 is not available (Linux older than 5.6), fallback is invoked instead.
+func withBeneath(op, root, relPath string, flags int, operation func(fd int) error, fallback func() error) error {
+	rootFd, err := openRoot(root)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(rootFd)
+
+	fd, err := openBeneath(rootFd, relPath, flags, 0)
+	if err == unix.ENOSYS {
+		return fallback()
+	} else if err != nil {
+		return beneathError(op, root, relPath, err)
+	}
+	defer unix.Close(fd)
+
+	if err = operation(fd); err != nil {
+		return joinedPathError(op, root, relPath, err)
+	}
+	return nil
+}
+
+//chmod(2) and utimensat(2) can't take O_PATH file descriptor, the magic link of it in procfs is used instead
+func procFdPath(fd int) string {
+	return fmt.Sprintf("/proc/self/fd/%d", fd)
+}
+
+func procFdAvailable() bool {
+	_, err := os.Stat("/proc/self/fd")
+	return err == nil
+}
+
+//error of the syscall, without the (procfs) path
+func syscallError(err error) error {
+	var pathError *os.PathError
+	if errors.As(err, &pathError) {
+		return pathError.Err
+	}
+	return err
+}
+
+func lchownBeneath(root, relPath string, uid, gid int) error {
+	return withBeneath("lchown", root, relPath, unix.O_PATH|unix.O_NOFOLLOW,
+		func(fd int) error { return unix.Fchownat(fd, "", uid, gid, unix.AT_EMPTY_PATH) },
+		func() error { return lchownFallback(root, relPath, uid, gid) })
+}
+
+func chmodBeneath(root, relPath string, mode os.FileMode) error {
+	if !procFdAvailable() {
+		return chmodFallback(root, relPath, mode)
+	}
+	return withBeneath("chmod", root, relPath, unix.O_PATH,
+		func(fd int) error { return syscallError(os.Chmod(procFdPath(fd), mode)) },
+		func() error { return chmodFallback(root, relPath, mode) })
+}
+
+func chtimesBeneath(root, relPath string, atime, mtime time.Time) error {
+	if !procFdAvailable() {
+		return chtimesFallback(root, relPath, atime, mtime)
+	}
+	return withBeneath("chtimes", root, relPath, unix.O_PATH,
+		func(fd int) error { return syscallError(os.Chtimes(procFdPath(fd), atime, mtime)) },
+		func() error { return chtimesFallback(root, relPath, atime, mtime) })
+}
+
+func symlinkBeneath(target, root, relPath string) error {
+	return withBeneath("symlink", root, filepath.Dir(relPath), unix.O_PATH|unix.O_DIRECTORY,
+		func(parentFd int) error { return unix.Symlinkat(target, parentFd, filepath.Base(relPath)) },
+		func() error { return symlinkFallback(target, root, relPath) })
+}
+
+func createBeneath(root, relPath string, perm os.FileMode) (f *os.File, err error) {
+	rootFd, err := openRoot(root)
+	if err != nil {
+		return
+	}
+	defer unix.Close(rootFd)
+
+	fd, err := openBeneath(rootFd, relPath, unix.O_WRONLY|unix.O_CREAT|unix.O_TRUNC, perm)
+	if err == unix.ENOSYS {
+		return createFallback(root, relPath, perm)
+	} else if err != nil {
+		return nil, beneathError("open", root, relPath, err)
+	}
+	return os.NewFile(uintptr(fd), filepath.Join(root, relPath)), nil
+}
+
+//each missing element is made with mkdirat(2) in the directory opened beneath root
+func mkdirAllBeneath(root, relPath string, perm os.FileMode) error {
+	relPath = filepath.Clean(relPath)
+	if relPath == "." {
+		return nil
+	}
+
+	rootFd, err := openRoot(root)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(rootFd)
+
+	parent := "."
+	for _, element := range strings.Split(relPath, string(filepath.Separator)) {
+		current := filepath.Join(parent, element)
+
+		fd, err := openBeneath(rootFd, current, unix.O_PATH|unix.O_DIRECTORY, 0)
+		if err == nil {
+			unix.Close(fd)
+			parent = current
+			continue
+		} else if err == unix.ENOSYS {
+			return mkdirAllFallback(root, relPath, perm)
+		} else if err != unix.ENOENT {
+			return beneathError("mkdir", root, current, err)
+		}
+
+		parentFd, err := openBeneath(rootFd, parent, unix.O_PATH|unix.O_DIRECTORY, 0)
+		if err != nil {
+			return beneathError("mkdir", root, current, err)
+		}
+		err = unix.Mkdirat(parentFd, element, uint32(perm.Perm()))
+		unix.Close(parentFd)
+		if err != nil && err != unix.EEXIST {
+			return joinedPathError("mkdir", root, current, err)
+		}
+		parent = current
+	}
+	return nil
+}
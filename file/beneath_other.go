@@ -0,0 +1,34 @@
+// +build !linux
+
+package file
+
+import (
+	"os"
+	"time"
+)
+
+//without openat2 the paths are checked with ResolveBeneath, which is not atomic with the operation
+
+func mkdirAllBeneath(root, relPath string, perm os.FileMode) error {
+	return mkdirAllFallback(root, relPath, perm)
+}
+
+func createBeneath(root, relPath string, perm os.FileMode) (*os.File, error) {
+	return createFallback(root, relPath, perm)
+}
+
+func symlinkBeneath(target, root, relPath string) error {
+	return symlinkFallback(target, root, relPath)
+}
+
+func lchownBeneath(root, relPath string, uid, gid int) error {
+	return lchownFallback(root, relPath, uid, gid)
+}
+
+func chmodBeneath(root, relPath string, mode os.FileMode) error {
+	return chmodFallback(root, relPath, mode)
+}
+
+func chtimesBeneath(root, relPath string, atime, mtime time.Time) error {
+	return chtimesFallback(root, relPath, atime, mtime)
+}
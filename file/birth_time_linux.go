@@ -0,0 +1,23 @@
+// +build linux
+
+package file
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+//uses statx(2), available since Linux 4.11, birth time also has to be supported by the filesystem
+func birthTime(path string) (born time.Time, err error) {
+	var stx unix.Statx_t
+	err = unix.Statx(unix.AT_FDCWD, path, unix.AT_SYMLINK_NOFOLLOW, unix.STATX_BTIME, &stx)
+	if err != nil {
+		return
+	}
+	if stx.Mask&unix.STATX_BTIME == 0 {
+		return born, errors.New("birth time not supported by the filesystem")
+	}
+	return time.Unix(stx.Btime.Sec, int64(stx.Btime.Nsec)), nil
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package file
+
+import (
+	"errors"
+	"time"
+)
+
+func birthTime(path string) (born time.Time, err error) {
+	return born, errors.New("birth time not supported on this platform")
+}
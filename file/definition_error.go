@@ -0,0 +1,25 @@
+package file
+
+import (
+	"fmt"
+	"strings"
+)
+
+//describes problems with a file definition, found before it was created or verified
+type DefinitionError struct {
+	Path     string
+	Problems []error
+}
+
+func (e *DefinitionError) Error() string {
+	messages := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		messages = append(messages, problem.Error())
+	}
+	return fmt.Sprintf("invalid definition of %s: %s", e.Path, strings.Join(messages, "; "))
+}
+
+//each of the problems, so that errors.Is and errors.As can be used on DefinitionError
+func (e *DefinitionError) Unwrap() []error {
+	return e.Problems
+}
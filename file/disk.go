@@ -0,0 +1,180 @@
+package file
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//Operations modifying the disk, performed when definitions are created and their attributes aligned.
+//Paths are relPath under root, which must not lead outside of root (PathEscapeError is returned if they do).
+//Empty root means relPath is used as is, e.g. for absolute Meta.Path.
+//Remove, RemoveAll, Rename and TempDir take paths as they are, they are used by filefactory to prepare and clean up.
+type Disk interface {
+	MkdirAll(root, relPath string, perm os.FileMode) error
+	//size bytes of contents are written, file is created or truncated
+	WriteFile(root, relPath string, contents io.Reader, size int64, perm os.FileMode) error
+	Symlink(target, root, relPath string) error
+	//does not follow the final symlink
+	Lchown(root, relPath string, uid, gid int) error
+	Chmod(root, relPath string, mode os.FileMode) error
+	Chtimes(root, relPath string, atime, mtime time.Time) error
+	//invoke AttributeHandler.Create or AttributeHandler.Align of a registered attribute
+	CreateAttribute(handler AttributeHandler, path string, value interface{}) error
+	AlignAttribute(handler AttributeHandler, path string, value interface{}) error
+	Remove(path string) error
+	RemoveAll(path string) error
+	Rename(oldPath, newPath string) error
+	//atomically swaps the two existing paths, ErrExchangeNotSupported if the system or file system can't
+	Exchange(path1, path2 string) error
+	TempDir(dir, pattern string) (name string, err error)
+}
+
+//returned by Disk.Exchange, e.g. on systems other than Linux
+var ErrExchangeNotSupported = errors.New("atomic exchange of paths is not supported")
+
+//Implemented by definitions which can be created on a given Disk (all definitions of def pkg are), so that e.g.
+// a dry run (see pkg plan) can record the operations rather than perform them.
+type DiskCreator interface {
+	CreateOn(disk Disk, root string) error
+}
+
+//Meta implements it, so does any definition embedding it
+type DiskAttributesAligner interface {
+	AlignAttributesOn(disk Disk, ownershipInAnyCase, modeIfApplicable, timesIfApplicable bool, optionalRoot ...string) (err error)
+}
+
+//Disk of the operating system.
+//On Linux (5.6 and newer), operations under root are performed through file descriptors opened with
+// openat2(2) RESOLVE_BENEATH, so that they can't escape root, even if a symlink is swapped in concurrently.
+//Elsewhere (and if openat2 is not available) the path is checked with ResolveBeneath first, which is not atomic
+// with the operation.
+type OsDisk struct{}
+
+func (OsDisk) MkdirAll(root, relPath string, perm os.FileMode) error {
+	if root == "" {
+		return os.MkdirAll(relPath, perm)
+	}
+	//root itself is trusted, it is made as it would be without the checks
+	if err := os.MkdirAll(root, 0777); err != nil {
+		return err
+	}
+	return mkdirAllBeneath(root, beneath(relPath), perm)
+}
+
+func (OsDisk) WriteFile(root, relPath string, contents io.Reader, size int64, perm os.FileMode) (err error) {
+	var f *os.File
+	if root == "" {
+		f, err = os.OpenFile(relPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	} else {
+		f, err = createBeneath(root, beneath(relPath), perm)
+	}
+	if err != nil {
+		return
+	}
+	_, err = io.CopyN(f, contents, size)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
+
+func (OsDisk) Symlink(target, root, relPath string) error {
+	if root == "" {
+		return os.Symlink(target, relPath)
+	}
+	return symlinkBeneath(target, root, beneath(relPath))
+}
+
+func (OsDisk) Lchown(root, relPath string, uid, gid int) error {
+	if root == "" {
+		return impl.OsLchown(relPath, uid, gid)
+	}
+	return lchownBeneath(root, beneath(relPath), uid, gid)
+}
+
+func (OsDisk) Chmod(root, relPath string, mode os.FileMode) error {
+	if root == "" {
+		return impl.OsChmod(relPath, mode)
+	}
+	return chmodBeneath(root, beneath(relPath), mode)
+}
+
+func (OsDisk) Chtimes(root, relPath string, atime, mtime time.Time) error {
+	if root == "" {
+		return impl.OsChtimes(relPath, atime, mtime)
+	}
+	return chtimesBeneath(root, beneath(relPath), atime, mtime)
+}
+
+func (OsDisk) CreateAttribute(handler AttributeHandler, path string, value interface{}) error {
+	return handler.Create(path, value)
+}
+
+func (OsDisk) AlignAttribute(handler AttributeHandler, path string, value interface{}) error {
+	return handler.Align(path, value)
+}
+
+func (OsDisk) Remove(path string) error                    { return os.Remove(path) }
+func (OsDisk) RemoveAll(path string) error                 { return os.RemoveAll(path) }
+func (OsDisk) Rename(oldPath, newPath string) error        { return os.Rename(oldPath, newPath) }
+func (OsDisk) TempDir(dir, pattern string) (string, error) { return ioutil.TempDir(dir, pattern) }
+
+func (OsDisk) Exchange(path1, path2 string) error {
+	return exchange(path1, path2)
+}
+
+//Fallback for systems without openat2: relPath is checked with ResolveBeneath, then the operation is performed on
+// the path. The check is not atomic with the operation.
+func resolveThen(root, relPath string, followFinal bool, operation func(path string) error) error {
+	path, err := ResolveBeneath(root, relPath, followFinal)
+	if err != nil {
+		return err
+	}
+	return operation(path)
+}
+
+func mkdirAllFallback(root, relPath string, perm os.FileMode) error {
+	return resolveThen(root, relPath, true, func(path string) error { return os.MkdirAll(path, perm) })
+}
+
+func createFallback(root, relPath string, perm os.FileMode) (f *os.File, err error) {
+	err = resolveThen(root, relPath, true, func(path string) (err error) {
+		f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+		return
+	})
+	return
+}
+
+func symlinkFallback(target, root, relPath string) error {
+	return resolveThen(root, relPath, false, func(path string) error { return os.Symlink(target, path) })
+}
+
+func lchownFallback(root, relPath string, uid, gid int) error {
+	return resolveThen(root, relPath, false, func(path string) error { return impl.OsLchown(path, uid, gid) })
+}
+
+func chmodFallback(root, relPath string, mode os.FileMode) error {
+	return resolveThen(root, relPath, true, func(path string) error { return impl.OsChmod(path, mode) })
+}
+
+func chtimesFallback(root, relPath string, atime, mtime time.Time) error {
+	return resolveThen(root, relPath, true, func(path string) error { return impl.OsChtimes(path, atime, mtime) })
+}
+
+//absolute relPath is taken as relative to root, as with ResolveBeneath
+func beneath(relPath string) string {
+	relPath = strings.TrimLeft(relPath, string(filepath.Separator))
+	if relPath == "" {
+		return "."
+	}
+	return relPath
+}
+
+func joinedPathError(op, root, relPath string, err error) error {
+	return &os.PathError{Op: op, Path: filepath.Join(root, relPath), Err: err}
+}
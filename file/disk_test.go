@@ -0,0 +1,115 @@
+package file_test
+
+import (
+	"bytes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+	"github.com/outo/filefactory/file"
+)
+
+var _ = Describe("pkg file disk.go unit test", func() {
+
+	var (
+		root,
+		outside string
+		disk file.OsDisk
+	)
+
+	BeforeEach(func() {
+		file.ResetImplementation()
+		var err error
+		root, err = ioutil.TempDir("", "disk-root-")
+		Expect(err).ShouldNot(HaveOccurred())
+		outside, err = ioutil.TempDir("", "disk-outside-")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(root, "dir"), 0777)).To(Succeed())
+		Expect(os.Symlink("dir", filepath.Join(root, "link-to-dir"))).To(Succeed())
+		Expect(os.Symlink(outside, filepath.Join(root, "link-outside"))).To(Succeed())
+		Expect(os.Symlink("../..", filepath.Join(root, "dir/link-up"))).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(outside, "victim"), []byte("untouched"), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+		os.RemoveAll(outside)
+	})
+
+	It("will perform the operations beneath root, following symlinks which stay beneath it", func() {
+		Expect(disk.MkdirAll(root, "link-to-dir/a/b", 0700)).To(Succeed())
+		Expect(disk.WriteFile(root, "link-to-dir/a/b/file", bytes.NewReader([]byte("contents and more")), 8, 0600)).To(Succeed())
+		Expect(disk.Symlink("file", root, "dir/a/b/symlink")).To(Succeed())
+		Expect(disk.Chmod(root, "dir/a/b/symlink", 0640)).To(Succeed())
+		modified := time.Unix(1500000000, 0)
+		Expect(disk.Chtimes(root, "dir/a/b/file", modified, modified)).To(Succeed())
+		Expect(disk.Lchown(root, "dir/a/b/symlink", os.Getuid(), os.Getgid())).To(Succeed())
+
+		contents, err := ioutil.ReadFile(filepath.Join(root, "dir/a/b/file"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(contents)).To(Equal("contents"))
+		info, err := os.Stat(filepath.Join(root, "dir/a/b/file"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0640)))
+		Expect(info.ModTime()).To(BeTemporally("==", modified))
+		info, err = os.Stat(filepath.Join(root, "dir/a"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(info.IsDir()).To(BeTrue())
+	})
+
+	It("will take absolute relPath as relative to root", func() {
+		Expect(disk.MkdirAll(root, "/dir/abs", 0700)).To(Succeed())
+		Expect(filepath.Join(root, "dir/abs")).To(BeADirectory())
+	})
+
+	DescribeTable("will refuse to operate outside of root, leaving it untouched",
+		func(operation func(disk file.Disk, root string) error) {
+			err := operation(disk, root)
+			Expect(err).To(BeAssignableToTypeOf(&file.PathEscapeError{}))
+
+			contents, err := ioutil.ReadFile(filepath.Join(outside, "victim"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(contents)).To(Equal("untouched"))
+			info, err := os.Stat(filepath.Join(outside, "victim"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+			entries, err := ioutil.ReadDir(outside)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+		},
+		Entry("MkdirAll through symlink", func(disk file.Disk, root string) error {
+			return disk.MkdirAll(root, "link-outside/new", 0777)
+		}),
+		Entry("MkdirAll lexically", func(disk file.Disk, root string) error {
+			return disk.MkdirAll(root, "dir/../../new", 0777)
+		}),
+		Entry("WriteFile through symlink", func(disk file.Disk, root string) error {
+			return disk.WriteFile(root, "link-outside/victim", bytes.NewReader(nil), 0, 0666)
+		}),
+		Entry("WriteFile through relative symlink", func(disk file.Disk, root string) error {
+			return disk.WriteFile(root, "dir/link-up/victim", bytes.NewReader(nil), 0, 0666)
+		}),
+		Entry("Symlink through symlink", func(disk file.Disk, root string) error {
+			return disk.Symlink("anything", root, "link-outside/new")
+		}),
+		Entry("Chmod of final symlink", func(disk file.Disk, root string) error {
+			return disk.Chmod(root, "link-outside/victim", 0777)
+		}),
+		Entry("Chtimes through symlink", func(disk file.Disk, root string) error {
+			return disk.Chtimes(root, "link-outside/victim", time.Now(), time.Now())
+		}),
+		Entry("Lchown through symlink", func(disk file.Disk, root string) error {
+			return disk.Lchown(root, "link-outside/victim", os.Getuid(), os.Getgid())
+		}),
+	)
+
+	It("will use the path as is when root is empty", func() {
+		path := filepath.Join(root, "link-outside", "absolute")
+		Expect(disk.MkdirAll("", path, 0777)).To(Succeed())
+		Expect(filepath.Join(outside, "absolute")).To(BeADirectory())
+	})
+})
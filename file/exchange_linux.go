@@ -0,0 +1,20 @@
+// +build linux
+
+package file
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+//renameat2(2) RENAME_EXCHANGE, Linux 3.15 and newer, not all file systems support it
+func exchange(path1, path2 string) error {
+	err := unix.Renameat2(unix.AT_FDCWD, path1, unix.AT_FDCWD, path2, unix.RENAME_EXCHANGE)
+	if err == unix.ENOSYS || err == unix.EINVAL {
+		return ErrExchangeNotSupported
+	} else if err != nil {
+		return &os.LinkError{Op: "exchange", Old: path1, New: path2, Err: err}
+	}
+	return nil
+}
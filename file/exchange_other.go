@@ -0,0 +1,7 @@
+// +build !linux
+
+package file
+
+func exchange(path1, path2 string) error {
+	return ErrExchangeNotSupported
+}
@@ -18,6 +18,11 @@ type Verifier interface {
 	Verify(root string) error
 }
 
+//implemented by definitions which can report problems with their attributes before they are created or verified
+type Validator interface {
+	Validate() error
+}
+
 type CommonAttributesGetter interface {
 	GetPath() string
 	GetMode() os.FileMode
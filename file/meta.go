@@ -27,9 +27,20 @@ type Meta struct {
 	Mode                     os.FileMode
 	Accessed                 time.Time
 	Modified                 time.Time
+	//change time (ctime) and birth time can't be set, if zero they won't be verified unless constrained
+	Changed                  time.Time
+	Born                     time.Time
 	Uid                      uint32
 	Gid                      uint32
+	//granularity of timestamps, both set and verified ones are truncated to it (zero means no truncation)
+	TimePrecision            time.Duration
+	//if present for a given timestamp, they replace exact comparison of that timestamp during verification
+	TimeConstraints          []attr.TimeConstraint
 	VerificationInstructions []verify.Instruction
+	//values of attribute types registered with RegisterAttribute
+	CustomAttributes         []interface{}
+	//problems encountered while populating, e.g. attribute resolution errors
+	problems                 []error
 }
 
 func NewFromPath(path string) (meta Meta, err error) {
@@ -45,9 +56,15 @@ func NewFromPath(path string) (meta Meta, err error) {
 		Mode:     info.Mode(),
 		Accessed: time.Unix(st.Atim.Sec, st.Atim.Nsec),
 		Modified: time.Unix(st.Mtim.Sec, st.Mtim.Nsec),
+		Changed:  time.Unix(st.Ctim.Sec, st.Ctim.Nsec),
 		Uid:      st.Uid,
 		Gid:      st.Gid,
 	}
+
+	//birth time is not available on every platform and filesystem, it stays zero then
+	if born, err := impl.BirthTime(path); err == nil {
+		meta.Born = born
+	}
 	return
 }
 
@@ -59,6 +76,14 @@ func (m Meta) String() string {
 	return fmt.Sprintf("%s %d %d %s %s %s", m.Mode.String(), m.Uid, m.Gid, m.Modified.Format(TimeLayout), m.Accessed.Format(TimeLayout), m.Path)
 }
 
+//truncates given time to this Meta's TimePrecision
+func (m Meta) TruncateTime(t time.Time) time.Time {
+	if m.TimePrecision <= 0 {
+		return t
+	}
+	return t.Truncate(m.TimePrecision)
+}
+
 func (m Meta) Should(verification verify.Instruction) bool {
 	doVerify := true
 	for _, verificationInstruction := range m.VerificationInstructions {
@@ -87,10 +112,10 @@ func (m Meta) Verify(root string) (err error) {
 
 	ex, err := impl.PathExists(path)
 	if err != nil {
-		verr.Add(diff.NotPresentOrNotAccessible, path, err)
+		verr.AddError(verify.NewDifference(diff.NotPresentOrNotAccessible, path, m.Path, nil, nil, err))
 		return verr
 	} else if !ex {
-		verr.Add(diff.NotPresentOrNotAccessible, path, errors.New(fmt.Sprintf("file does not exist")))
+		verr.AddError(verify.NewDifference(diff.NotPresentOrNotAccessible, path, m.Path, nil, nil, errors.New(fmt.Sprintf("file does not exist"))))
 		return verr
 	}
 
@@ -100,103 +125,279 @@ func (m Meta) Verify(root string) (err error) {
 	}
 
 	if meta.Mode & os.ModeType != m.Mode & os.ModeType {
-		verr.Add(diff.ModeType, path, errors.New(fmt.Sprintf("expected %s, actual %s", m.Mode, meta.Mode)))
+		verr.AddError(verify.NewDifference(diff.ModeType, path, m.Path, m.Mode, meta.Mode, errors.New(fmt.Sprintf("expected %s, actual %s", m.Mode, meta.Mode))))
 		return verr
 	}
 
 	if m.Should(verify.ModePerm(true)) {
 		if meta.Mode & os.ModePerm != m.Mode & os.ModePerm{
-			verr.Add(diff.ModePerm, path, errors.New(fmt.Sprintf("expected %s, actual %s", m.Mode, meta.Mode)))
+			verr.AddError(verify.NewDifference(diff.ModePerm, path, m.Path, m.Mode, meta.Mode, errors.New(fmt.Sprintf("expected %s, actual %s", m.Mode, meta.Mode))))
 		}
 	}
 
 	if m.Should(verify.Uid(true)) {
 		if meta.Uid != m.Uid {
-			verr.Add(diff.Owner, path, errors.New(fmt.Sprintf("expected %d, actual %d", m.Uid, meta.Uid)))
+			verr.AddError(verify.NewDifference(diff.Owner, path, m.Path, m.Uid, meta.Uid, errors.New(fmt.Sprintf("expected %d, actual %d", m.Uid, meta.Uid))))
 		}
 	}
 
 	if m.Should(verify.Gid(true)) {
 		if meta.Gid != m.Gid {
-			verr.Add(diff.Group, path, errors.New(fmt.Sprintf("expected %d, actual %d", m.Gid, meta.Gid)))
+			verr.AddError(verify.NewDifference(diff.Group, path, m.Path, m.Gid, meta.Gid, errors.New(fmt.Sprintf("expected %d, actual %d", m.Gid, meta.Gid))))
 		}
 	}
 
 	if m.Should(verify.AccessedTime(true)) {
-		if !meta.Accessed.Equal(m.Accessed) {
-			verr.Add(diff.AccTime, path, errors.New(fmt.Sprintf("expected %s, actual %s", m.Accessed.Format(TimeLayout), meta.Accessed.Format(TimeLayout))))
+		if m.hasTimeConstraints(attr.AccessedTimeKind) {
+			if err = m.verifyTimeConstraints(verr, attr.AccessedTimeKind, diff.AccTime, root, path, meta); err != nil {
+				return
+			}
+		} else if !m.TruncateTime(meta.Accessed).Equal(m.TruncateTime(m.Accessed)) {
+			verr.AddError(verify.NewDifference(diff.AccTime, path, m.Path, m.Accessed, meta.Accessed, errors.New(fmt.Sprintf("expected %s, actual %s", m.Accessed.Format(TimeLayout), meta.Accessed.Format(TimeLayout)))))
 		}
 	}
 
 	if m.Should(verify.ModifiedTime(true)) {
-		if !meta.Modified.Equal(m.Modified) {
-			verr.Add(diff.ModTime, path, errors.New(fmt.Sprintf("expected %s, actual %s", m.Modified.Format(TimeLayout), meta.Modified.Format(TimeLayout))))
+		if m.hasTimeConstraints(attr.ModifiedTimeKind) {
+			if err = m.verifyTimeConstraints(verr, attr.ModifiedTimeKind, diff.ModTime, root, path, meta); err != nil {
+				return
+			}
+		} else if !m.TruncateTime(meta.Modified).Equal(m.TruncateTime(m.Modified)) {
+			verr.AddError(verify.NewDifference(diff.ModTime, path, m.Path, m.Modified, meta.Modified, errors.New(fmt.Sprintf("expected %s, actual %s", m.Modified.Format(TimeLayout), meta.Modified.Format(TimeLayout)))))
+		}
+	}
+
+	if m.Should(verify.ChangedTime(true)) {
+		if err = m.verifyUnsettableTime(verr, attr.ChangedTimeKind, diff.ChangeTime, root, path, meta); err != nil {
+			return
 		}
 	}
 
+	if m.Should(verify.BirthTime(true)) {
+		if err = m.verifyUnsettableTime(verr, attr.BirthTimeKind, diff.BirthTime, root, path, meta); err != nil {
+			return
+		}
+	}
+
+	if err = m.verifyCustomAttributes(verr, path); err != nil {
+		return
+	}
+
 	return verr.MapToNilIfNone()
 }
 
+//timestamps which can't be set are only verified when expected value or constraints were given
+func (m Meta) verifyUnsettableTime(verr *verify.Errors, kind attr.TimeKind, difference diff.FileDifference, root, path string, actual Meta) (err error) {
+	expected := m.TimeOf(kind)
+	if !m.hasTimeConstraints(kind) && expected.IsZero() {
+		return
+	}
+	if actual.TimeOf(kind).IsZero() {
+		verr.AddError(verify.NewDifference(difference, path, m.Path, expected, nil, errors.New("expected timestamp is not available on this platform or filesystem")))
+		return
+	}
+	if m.hasTimeConstraints(kind) {
+		return m.verifyTimeConstraints(verr, kind, difference, root, path, actual)
+	}
+	if !m.TruncateTime(actual.TimeOf(kind)).Equal(m.TruncateTime(expected)) {
+		verr.AddError(verify.NewDifference(difference, path, m.Path, expected, actual.TimeOf(kind), errors.New(fmt.Sprintf("expected %s, actual %s", expected.Format(TimeLayout), actual.TimeOf(kind).Format(TimeLayout)))))
+	}
+	return
+}
+
+//returns the timestamp of given kind
+func (m Meta) TimeOf(kind attr.TimeKind) time.Time {
+	switch kind {
+	case attr.AccessedTimeKind:
+		return m.Accessed
+	case attr.ChangedTimeKind:
+		return m.Changed
+	case attr.BirthTimeKind:
+		return m.Born
+	default:
+		return m.Modified
+	}
+}
+
+func (m Meta) hasTimeConstraints(kind attr.TimeKind) bool {
+	for _, constraint := range m.TimeConstraints {
+		if constraint.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Meta) verifyTimeConstraints(verr *verify.Errors, kind attr.TimeKind, difference diff.FileDifference, root, path string, actual Meta) (err error) {
+	actualTime := actual.TimeOf(kind)
+	for _, constraint := range m.TimeConstraints {
+		if constraint.Kind != kind {
+			continue
+		}
+		if !constraint.After.IsZero() && !m.isAfter(actualTime, constraint.After) {
+			verr.AddError(verify.NewDifference(difference, path, m.Path, constraint, actualTime, errors.New(fmt.Sprintf("expected after %s, actual %s", constraint.After.Format(TimeLayout), actualTime.Format(TimeLayout)))))
+		}
+		if !constraint.Before.IsZero() && !m.isAfter(constraint.Before, actualTime) {
+			verr.AddError(verify.NewDifference(difference, path, m.Path, constraint, actualTime, errors.New(fmt.Sprintf("expected before %s, actual %s", constraint.Before.Format(TimeLayout), actualTime.Format(TimeLayout)))))
+		}
+		if constraint.Within != 0 {
+			now := impl.TimeNow()
+			if m.TruncateTime(actualTime).Before(m.TruncateTime(now.Add(-constraint.Within))) || m.TruncateTime(actualTime).After(m.TruncateTime(now.Add(constraint.Within))) {
+				verr.AddError(verify.NewDifference(difference, path, m.Path, constraint, actualTime, errors.New(fmt.Sprintf("expected within %s of %s, actual %s", constraint.Within, now.Format(TimeLayout), actualTime.Format(TimeLayout)))))
+			}
+		}
+		if constraint.NewerThan != "" {
+			//the reference file is looked up beneath root too, be it through a symlink
+			referencePath, err := ResolveBeneath(root, constraint.NewerThan, true)
+			if err != nil {
+				return err
+			}
+			reference, err := impl.WrapNewFromPath(referencePath)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					return err
+				}
+				verr.AddError(verify.NewDifference(difference, path, m.Path, constraint, actualTime, errors.New(fmt.Sprintf("expected newer than %s, which does not exist", referencePath))))
+				continue
+			}
+			if !m.isAfter(actualTime, reference.TimeOf(kind)) {
+				verr.AddError(verify.NewDifference(difference, path, m.Path, constraint, actualTime, errors.New(fmt.Sprintf("expected newer than %s of %s, actual %s", reference.TimeOf(kind).Format(TimeLayout), referencePath, actualTime.Format(TimeLayout)))))
+			}
+		}
+	}
+	return
+}
+
+//With TimePrecision, timestamps within the same granule can't be told apart, so either of them counts as after the other.
+//Otherwise t has to be strictly after other.
+func (m Meta) isAfter(t, other time.Time) bool {
+	if m.TimePrecision <= 0 {
+		return t.After(other)
+	}
+	return !m.TruncateTime(t).Before(m.TruncateTime(other))
+}
+
 func (m Meta) AlignAttributes(ownership, mode, times bool, optionalRoot ...string) (err error) {
+	return m.AlignAttributesOn(impl.Disk, ownership, mode, times, optionalRoot...)
+}
+
+//As AlignAttributes, with the changes made through disk.
+//Relative Meta.Path is checked with ResolveBeneath first, so that nothing is touched if it leads outside of root,
+// the disk then performs each of the operations under root (for OsDisk see its guarantees).
+func (m Meta) AlignAttributesOn(disk Disk, ownership, mode, times bool, optionalRoot ...string) (err error) {
 
-	var path string
+	var root, relPath, path string
 	if filepath.IsAbs(m.Path) {
 		if filepath.Join(optionalRoot...) != "" {
 			return errors.New(ErrorMessageRootCannotBeUsedWithAbsoluteMetaPath)
 		} else {
-			path = m.Path
+			relPath, path = m.Path, m.Path
 		}
 	} else {
-		if filepath.Join(optionalRoot...) == "" {
+		root = filepath.Join(optionalRoot...)
+		if root == "" {
 			return errors.New(ErrorMessageRelativePathHasToBeUsedWithRoot)
 		}
-		path = filepath.Join(filepath.Join(optionalRoot...), m.Path)
+		relPath = m.Path
+		//chmod and chtimes follow symlinks, lchown does not
+		path, err = ResolveBeneath(root, relPath, !m.isSymlink())
+		if err != nil {
+			return
+		}
 	}
 
 	if ownership {
-		err = impl.OsLchown(path, int(m.Uid), int(m.Gid))
+		err = disk.Lchown(root, relPath, int(m.Uid), int(m.Gid))
 		if err != nil {
 			return err
 		}
 	}
 
 	if mode && !m.isSymlink() {
-		err = impl.OsChmod(path, m.Mode)
+		err = disk.Chmod(root, relPath, m.Mode)
 		if err != nil {
 			return err
 		}
 	}
 
 	if times && !m.isSymlink() {
-		err = impl.OsChtimes(path, m.Accessed, m.Modified)
+		err = disk.Chtimes(root, relPath, m.TruncateTime(m.Accessed), m.TruncateTime(m.Modified))
 		if err != nil {
 			return err
 		}
 	}
-	return
+
+	return m.alignCustomAttributes(disk, path)
 }
 
 // will interpret variadic input with attributes and instructions and set fields of this Meta
 func (m *Meta) Populate(relPath string, attributesAndInstructions ...interface{}) {
 	for _, attribute := range attributesAndInstructions {
-		switch catt := attribute.(type) {
-		case os.FileMode:
-			m.Mode = catt
-		case attr.AccessedTime:
-			m.Accessed = time.Time(catt)
-		case attr.ModifiedTime:
-			m.Modified = time.Time(catt)
-		case attr.Uid:
-			m.Uid = uint32(catt)
-		case attr.Gid:
-			m.Gid = uint32(catt)
-		case verify.Instruction:
-			m.VerificationInstructions = append(m.VerificationInstructions, catt)
+		if resolver, ok := attribute.(attr.Resolver); ok {
+			resolved, err := resolver.Resolve()
+			if err != nil {
+				m.problems = append(m.problems, err)
+				continue
+			}
+			if _, ok := registeredAttributeOf(resolved); !ok {
+				m.problems = append(m.problems, errors.New(fmt.Sprintf("%T resolved to unknown attribute type %T", resolver, resolved)))
+				continue
+			}
+			attribute = resolved
+		}
+		registered, ok := registeredAttributeOf(attribute)
+		if !ok {
+			continue
+		}
+		if registered.populate != nil {
+			registered.populate(m, attribute)
+		} else {
+			m.setCustomAttribute(attribute)
 		}
 	}
 	m.Path = relPath
 }
 
+//tells whether Populate recognises given attribute or instruction, built-in or registered with RegisterAttribute.
+//Resolver is resolved first and its value is checked, the one failing to resolve is recognised (Populate records
+// the error).
+func IsMetaAttribute(attribute interface{}) bool {
+	if resolver, ok := attribute.(attr.Resolver); ok {
+		resolved, err := resolver.Resolve()
+		if err != nil {
+			return true
+		}
+		attribute = resolved
+	}
+	_, ok := registeredAttributeOf(attribute)
+	return ok
+}
+
+//will record a problem for each attribute which is neither recognised by Populate nor by the optional recognised function,
+// constructor names the definition constructor in the problem (e.g. "def.Dir").
+//Resolvers are skipped, Populate records a problem for those resolving to an unknown attribute type.
+func (m *Meta) RejectUnknownAttributes(constructor string, attributes []interface{}, recognised func(attribute interface{}) bool) {
+	for _, attribute := range attributes {
+		if _, isResolver := attribute.(attr.Resolver); isResolver {
+			continue
+		}
+		if IsMetaAttribute(attribute) || recognised != nil && recognised(attribute) {
+			continue
+		}
+		m.problems = append(m.problems, errors.New(fmt.Sprintf("%s: unknown attribute type %T", constructor, attribute)))
+	}
+}
+
+//will return DefinitionError if there were problems populating this Meta, nil otherwise
+func (m Meta) Validate() error {
+	if len(m.problems) == 0 {
+		return nil
+	}
+	return &DefinitionError{
+		Path:     m.Path,
+		Problems: m.problems,
+	}
+}
 
 func (m Meta) GetPath() string        { return m.Path }
 func (m Meta) GetMode() os.FileMode   { return m.Mode }
@@ -2,7 +2,10 @@ package file_test
 
 import (
 	"errors"
+	"fmt"
+	"io/ioutil"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/outo/filefactory/testingaids/mock"
 	"os"
@@ -43,6 +46,11 @@ var _ = Describe("pkg file meta.go unit test", func() {
 						Sec:  expectedModifiedTime.Truncate(time.Second).Unix(),
 						Nsec: int64(expectedModifiedTime.Nanosecond()),
 					},
+					//change time is an hour after modified time
+					Ctim: syscall.Timespec{
+						Sec:  expectedModifiedTime.Add(time.Hour).Truncate(time.Second).Unix(),
+						Nsec: int64(expectedModifiedTime.Nanosecond()),
+					},
 				}
 			},
 		}
@@ -120,6 +128,34 @@ var _ = Describe("pkg file meta.go unit test", func() {
 			Expect(actualFileMeta.Gid).To(Equal(expectedGid))
 			Expect(actualFileMeta.Accessed).To(BeTemporally("==", expectedAccessedTime))
 			Expect(actualFileMeta.Modified).To(BeTemporally("==", expectedModifiedTime))
+			Expect(actualFileMeta.Changed).To(BeTemporally("==", expectedModifiedTime.Add(time.Hour)))
+		})
+		It("will retrieve birth time if available", func() {
+			expectedBirthTime := time.Now().Add(-time.Hour)
+			file.MockForTest(func(modifyThis *file.Implementation) {
+				modifyThis.OsLstat = func(name string) (os.FileInfo, error) {
+					return mockedFileInfo(name, 0644, 1, 2, time.Now(), time.Now()), noError
+				}
+				modifyThis.BirthTime = func(path string) (born time.Time, err error) {
+					return expectedBirthTime, noError
+				}
+			})
+			actualFileMeta, err := file.NewFromPath("does not matter")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(actualFileMeta.Born).To(BeTemporally("==", expectedBirthTime))
+		})
+		It("will leave birth time zero if it isn't available", func() {
+			file.MockForTest(func(modifyThis *file.Implementation) {
+				modifyThis.OsLstat = func(name string) (os.FileInfo, error) {
+					return mockedFileInfo(name, 0644, 1, 2, time.Now(), time.Now()), noError
+				}
+				modifyThis.BirthTime = func(path string) (born time.Time, err error) {
+					return born, anError
+				}
+			})
+			actualFileMeta, err := file.NewFromPath("does not matter")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(actualFileMeta.Born).To(BeZero())
 		})
 	})
 	Describe("given Meta", func() {
@@ -169,19 +205,43 @@ var _ = Describe("pkg file meta.go unit test", func() {
 					m.AlignAttributes(true, true, true)
 					Expect(actualPath).To(Equal(expectedPath))
 				})
-				It("will join relative Meta.Path to root, when root parameter was provided)", func() {
+				It("will pass relative Meta.Path to the disk along with root, when root parameter was provided)", func() {
 					const expectedPath = "a/relative/path"
 					m.Path = expectedPath
-					actualPath := ""
+					var actualRoot, actualPath string
+					disk := mock.NewDisk()
+					disk.LchownFunc = func(root, relPath string, uid, gid int) error {
+						actualRoot, actualPath = root, relPath
+						return anError
+					}
+					specifiedRootPath := "/a/root/path/passed/in"
+					m.AlignAttributesOn(disk, true, true, true, specifiedRootPath)
+					Expect(actualRoot).To(Equal(specifiedRootPath))
+					Expect(actualPath).To(Equal(expectedPath))
+				})
+				It("will align through impl.Disk when invoked as AlignAttributes", func() {
+					m.Path = "a/relative/path"
+					var actualPath string
+					disk := mock.NewDisk()
+					disk.ChmodFunc = func(root, relPath string, mode os.FileMode) error {
+						actualPath = filepath.Join(root, relPath)
+						return nil
+					}
 					file.MockForTest(func(modifyThis *file.Implementation) {
-						modifyThis.OsLchown = func(name string, uid int, gid int) error {
-							actualPath = name
-							return anError
-						}
+						modifyThis.Disk = disk
 					})
-					specifiedRootPath := "/a/root/path/passed/in"
-					m.AlignAttributes(true, true, true, specifiedRootPath)
-					Expect(actualPath).To(Equal(filepath.Join(specifiedRootPath, expectedPath)))
+					Expect(m.AlignAttributes(false, true, false, "/a/root")).To(Succeed())
+					Expect(actualPath).To(Equal("/a/root/a/relative/path"))
+				})
+				It("will refuse relative Meta.Path leading outside of root, without touching anything", func() {
+					m.Path = "../../etc/passwd"
+					var actualPath *string
+					file.MockForTest(func(modifyThis *file.Implementation) {
+						modifyThis.OsLchown = mock.OsLchown(noError, &actualPath, nil, nil)
+					})
+					actualErr := m.AlignAttributes(true, true, true, "/a/root/path")
+					Expect(actualErr).To(BeAssignableToTypeOf(&file.PathEscapeError{}))
+					Expect(actualPath).To(BeNil())
 				})
 			})
 
@@ -230,20 +290,22 @@ var _ = Describe("pkg file meta.go unit test", func() {
 				})
 				It("will return immediately with os.Lchown error", func() {
 					expectedError := errors.New("os.Lchown error")
-					var (
-						actualOsChmodPath,
-						actualOsChtimesPath *string
-					)
-					file.MockForTest(func(modifyThis *file.Implementation) {
-						modifyThis.OsLchown = mock.OsLchown(expectedError, nil, nil, nil)
-						modifyThis.OsChmod = mock.OsChmod(anError, &actualOsChmodPath, nil)
-						modifyThis.OsChtimes = mock.OsChtimes(anError, &actualOsChtimesPath, nil, nil)
-					})
+					var chmodInvoked, chtimesInvoked bool
+					disk := mock.NewDisk()
+					disk.LchownFunc = func(root, relPath string, uid, gid int) error { return expectedError }
+					disk.ChmodFunc = func(root, relPath string, mode os.FileMode) error {
+						chmodInvoked = true
+						return anError
+					}
+					disk.ChtimesFunc = func(root, relPath string, atime, mtime time.Time) error {
+						chtimesInvoked = true
+						return anError
+					}
 
-					actualError := m.AlignAttributes(true, true, true, "just to jump through the path routine")
+					actualError := m.AlignAttributesOn(disk, true, true, true, "just to jump through the path routine")
 					Expect(actualError).Should(MatchError(expectedError))
-					Expect(actualOsChmodPath).To(BeNil())
-					Expect(actualOsChtimesPath).To(BeNil())
+					Expect(chmodInvoked).To(BeFalse())
+					Expect(chtimesInvoked).To(BeFalse())
 				})
 			})
 
@@ -307,6 +369,20 @@ var _ = Describe("pkg file meta.go unit test", func() {
 					Expect(*actualAccTime).To(BeTemporally("==", expectedAccessed))
 					Expect(*actualModTime).To(BeTemporally("==", expectedModified))
 				})
+				It("will truncate times to TimePrecision before invoking os.Chtimes", func() {
+					var actualAccTime, actualModTime *time.Time
+					file.MockForTest(func(modifyThis *file.Implementation) {
+						modifyThis.OsChtimes = mock.OsChtimes(noError, nil, &actualAccTime, &actualModTime)
+					})
+
+					m.Path = "/expectedPath"
+					m.Accessed = time.Unix(1500000001, 999999999)
+					m.Modified = time.Unix(1500000003, 1)
+					m.TimePrecision = 2 * time.Second
+					m.AlignAttributes(false, false, true)
+					Expect(*actualAccTime).To(BeTemporally("==", time.Unix(1500000000, 0)))
+					Expect(*actualModTime).To(BeTemporally("==", time.Unix(1500000002, 0)))
+				})
 				It("will not invoke os.Chtimes if it is a symlink even when times are to be aligned", func() {
 					var (
 						actualPath *string
@@ -475,6 +551,9 @@ var _ = Describe("pkg file meta.go unit test", func() {
 					Expect(actualError).To(BeAssignableToTypeOf(&verify.Errors{}))
 					vErr := actualError.(*verify.Errors)
 					Expect(vErr.CombinedFileDifference).To(Equal(diff.ModePerm))
+					Expect(vErr.Errors[0].RelPath).To(Equal(originalPath))
+					Expect(vErr.Errors[0].Expected).To(Equal(os.FileMode(176)))
+					Expect(vErr.Errors[0].Actual).To(Equal(os.FileMode(12)))
 
 					m.Mode = 12
 					actualError = m.Verify("does not matter")
@@ -606,6 +685,234 @@ var _ = Describe("pkg file meta.go unit test", func() {
 					actualError := m.Verify("does not matter")
 					Expect(actualError).ShouldNot(HaveOccurred())
 				})
+				It("will compare times truncated to TimePrecision", func() {
+					file.MockForTest(func(modifyThis *file.Implementation) {
+						modifyThis.WrapNewFromPath = func(path string) (meta file.Meta, err error) {
+							meta = m
+							meta.Accessed = time.Unix(1500000001, 0)
+							meta.Modified = time.Unix(1500000002, 0)
+							return meta, noError
+						}
+					})
+					m.Accessed = time.Unix(1500000001, 999999999)
+					m.Modified = time.Unix(1500000002, 5000)
+
+					actualError := m.Verify("does not matter")
+					Expect(actualError).Should(HaveOccurred())
+					Expect(actualError.(*verify.Errors).CombinedFileDifference).To(Equal(diff.AccTime | diff.ModTime))
+
+					m.TimePrecision = time.Second
+					actualError = m.Verify("does not matter")
+					Expect(actualError).ShouldNot(HaveOccurred())
+
+					m.Modified = time.Unix(1500000003, 0)
+					actualError = m.Verify("does not matter")
+					Expect(actualError).Should(HaveOccurred())
+					Expect(actualError.(*verify.Errors).CombinedFileDifference).To(Equal(diff.ModTime))
+				})
+				Describe("with relative time constraints", func() {
+					reference := time.Unix(1500000000, 0)
+
+					BeforeEach(func() {
+						file.MockForTest(func(modifyThis *file.Implementation) {
+							modifyThis.WrapNewFromPath = func(path string) (meta file.Meta, err error) {
+								if path == filepath.Join("/root", "reference") {
+									meta.Modified = reference
+									meta.Accessed = reference.Add(time.Hour)
+									return
+								}
+								if path == filepath.Join("/root", "missing") {
+									err = os.ErrNotExist
+									return
+								}
+								meta = m
+								meta.Accessed = reference.Add(time.Minute)
+								meta.Modified = reference.Add(time.Minute)
+								return meta, noError
+							}
+							modifyThis.TimeNow = func() time.Time {
+								return reference.Add(2 * time.Minute)
+							}
+						})
+						//would fail exact comparison
+						m.Accessed = time.Now()
+						m.Modified = time.Now()
+					})
+
+					DescribeTable("will replace exact comparison of the timestamp the constraints apply to",
+						func(constraint attr.TimeConstraint, expectedDifference diff.FileDifference) {
+							m.Accessed = reference.Add(time.Minute)
+							m.Modified = reference.Add(time.Minute)
+							m.Populate(m.Path, constraint)
+							actualError := m.Verify("/root")
+							if expectedDifference == 0 {
+								Expect(actualError).ShouldNot(HaveOccurred())
+								return
+							}
+							Expect(actualError).Should(HaveOccurred())
+							Expect(actualError.(*verify.Errors).CombinedFileDifference).To(Equal(expectedDifference))
+						},
+						Entry("modified after, satisfied", attr.ModifiedAfter(reference), diff.FileDifference(0)),
+						Entry("modified after, violated", attr.ModifiedAfter(reference.Add(time.Hour)), diff.ModTime),
+						Entry("modified before, satisfied", attr.ModifiedBefore(reference.Add(time.Hour)), diff.FileDifference(0)),
+						Entry("modified before, violated", attr.ModifiedBefore(reference), diff.ModTime),
+						Entry("modified within, satisfied", attr.ModifiedWithin(time.Minute), diff.FileDifference(0)),
+						Entry("modified within, violated", attr.ModifiedWithin(time.Second), diff.ModTime),
+						Entry("modified newer than, satisfied", attr.ModifiedNewerThan("reference"), diff.FileDifference(0)),
+						Entry("modified newer than non-existent file", attr.ModifiedNewerThan("missing"), diff.ModTime),
+						Entry("accessed after, satisfied", attr.AccessedAfter(reference), diff.FileDifference(0)),
+						Entry("accessed after, violated", attr.AccessedAfter(reference.Add(time.Hour)), diff.AccTime),
+						Entry("accessed before, violated", attr.AccessedBefore(reference), diff.AccTime),
+						Entry("accessed within, violated", attr.AccessedWithin(time.Second), diff.AccTime),
+						Entry("accessed newer than, violated", attr.AccessedNewerThan("reference"), diff.AccTime),
+					)
+
+					It("will only replace exact comparison of the timestamp the constraints apply to", func() {
+						m.Populate(m.Path, attr.ModifiedAfter(reference))
+						actualError := m.Verify("/root")
+						Expect(actualError).Should(HaveOccurred())
+						Expect(actualError.(*verify.Errors).CombinedFileDifference).To(Equal(diff.AccTime))
+					})
+
+					It("will not evaluate constraints if verification of the timestamp wasn't requested", func() {
+						m.Populate(m.Path, attr.ModifiedAfter(reference.Add(time.Hour)), attr.AccessedAfter(reference.Add(time.Hour)), verify.ModifiedTime(false), verify.AccessedTime(false))
+						actualError := m.Verify("/root")
+						Expect(actualError).ShouldNot(HaveOccurred())
+					})
+
+					It("will describe violated constraint", func() {
+						m.Populate(m.Path, attr.ModifiedBefore(reference), attr.AccessedAfter(reference))
+						actualError := m.Verify("/root")
+						Expect(actualError).Should(HaveOccurred())
+						verErr := actualError.(*verify.Errors)
+						Expect(verErr.Errors).To(HaveLen(1))
+						Expect(verErr.Errors[0].Err).To(MatchError(fmt.Sprintf("expected before %s, actual %s", reference.Format(file.TimeLayout), reference.Add(time.Minute).Format(file.TimeLayout))))
+					})
+
+					DescribeTable("will compare timestamps truncated to TimePrecision, as exact comparison does",
+						func(constraint attr.TimeConstraint, expectedDifference diff.FileDifference) {
+							//stored with whole seconds only, while the constraints are finer
+							m.Accessed = reference.Add(time.Minute)
+							m.Modified = reference.Add(time.Minute)
+							m.Populate(m.Path, attr.TimePrecision(time.Second), constraint)
+							actualError := m.Verify("/root")
+							if expectedDifference == 0 {
+								Expect(actualError).ShouldNot(HaveOccurred())
+								return
+							}
+							Expect(actualError).Should(HaveOccurred())
+							Expect(actualError.(*verify.Errors).CombinedFileDifference).To(Equal(expectedDifference))
+						},
+						Entry("modified after, within the same second", attr.ModifiedAfter(reference.Add(time.Minute+300*time.Millisecond)), diff.FileDifference(0)),
+						Entry("modified after, a second later", attr.ModifiedAfter(reference.Add(time.Minute+time.Second)), diff.ModTime),
+						Entry("modified before, within the same second", attr.ModifiedBefore(reference.Add(time.Minute+700*time.Millisecond)), diff.FileDifference(0)),
+						Entry("modified before, a second earlier", attr.ModifiedBefore(reference.Add(time.Minute-time.Second)), diff.ModTime),
+						Entry("modified within, bound within the same second", attr.ModifiedWithin(time.Minute-200*time.Millisecond), diff.FileDifference(0)),
+						Entry("modified within, a second short", attr.ModifiedWithin(time.Minute-time.Second), diff.ModTime),
+					)
+
+					It("will report reference file leading outside of root as a definition problem, and not look it up", func() {
+						m.Populate(m.Path, attr.ModifiedNewerThan("../../etc/passwd"))
+						Expect(m.TimeConstraints).To(BeEmpty())
+
+						err := m.Validate()
+						Expect(err).To(BeAssignableToTypeOf(&file.DefinitionError{}))
+						Expect(err.(*file.DefinitionError).Problems).To(HaveLen(1))
+						Expect(err.(*file.DefinitionError).Problems[0]).To(BeAssignableToTypeOf(&file.PathEscapeError{}))
+						Expect(err).To(MatchError(ContainSubstring("../../etc/passwd escapes root: path leads outside of root")))
+					})
+
+					It("will return PathEscapeError if reference file resolves outside of root through a symlink", func() {
+						tempDir, err := ioutil.TempDir("", "meta-newer-than-test-")
+						Expect(err).ShouldNot(HaveOccurred())
+						defer os.RemoveAll(tempDir)
+						Expect(os.Symlink("/etc/passwd", filepath.Join(tempDir, "reference"))).To(Succeed())
+
+						m.Populate(m.Path, attr.ModifiedNewerThan("reference"))
+						Expect(m.Validate()).ShouldNot(HaveOccurred())
+						actualError := m.Verify(tempDir)
+						Expect(actualError).To(BeAssignableToTypeOf(&file.PathEscapeError{}))
+					})
+
+					It("will return error of retrieving reference file other than it does not exist", func() {
+						expectedError := errors.New("reference error")
+						file.MockForTest(func(modifyThis *file.Implementation) {
+							wrapped := modifyThis.WrapNewFromPath
+							modifyThis.WrapNewFromPath = func(path string) (meta file.Meta, err error) {
+								if path == filepath.Join("/root", "reference") {
+									return meta, expectedError
+								}
+								return wrapped(path)
+							}
+						})
+						m.Populate(m.Path, attr.ModifiedNewerThan("reference"))
+						actualError := m.Verify("/root")
+						Expect(actualError).Should(MatchError(expectedError))
+					})
+				})
+				Describe("change and birth time", func() {
+					changed := time.Unix(1500000000, 0)
+					born := time.Unix(1400000000, 0)
+
+					BeforeEach(func() {
+						file.MockForTest(func(modifyThis *file.Implementation) {
+							modifyThis.WrapNewFromPath = func(path string) (meta file.Meta, err error) {
+								meta = m
+								meta.Changed = changed
+								meta.Born = born
+								return meta, noError
+							}
+						})
+					})
+
+					It("will not verify them if neither expected value nor constraints were given", func() {
+						Expect(m.Verify("does not matter")).ShouldNot(HaveOccurred())
+					})
+
+					It("will verify change time is unchanged since snapshot", func() {
+						m.Populate(m.Path, attr.ChangedTime(changed))
+						Expect(m.Verify("does not matter")).ShouldNot(HaveOccurred())
+
+						m.Populate(m.Path, attr.ChangedTime(changed.Add(-time.Second)))
+						actualError := m.Verify("does not matter")
+						Expect(actualError).Should(HaveOccurred())
+						Expect(actualError.(*verify.Errors).CombinedFileDifference).To(Equal(diff.ChangeTime))
+
+						m.Populate(m.Path, verify.ChangedTime(false))
+						Expect(m.Verify("does not matter")).ShouldNot(HaveOccurred())
+					})
+
+					It("will verify birth time", func() {
+						m.Populate(m.Path, attr.BirthTime(born))
+						Expect(m.Verify("does not matter")).ShouldNot(HaveOccurred())
+
+						m.Populate(m.Path, attr.BirthTime(born.Add(time.Second)))
+						actualError := m.Verify("does not matter")
+						Expect(actualError).Should(HaveOccurred())
+						Expect(actualError.(*verify.Errors).CombinedFileDifference).To(Equal(diff.BirthTime))
+					})
+
+					It("will evaluate constraints on them", func() {
+						m.Populate(m.Path, attr.ChangedBefore(changed.Add(time.Second)), attr.BornAfter(born.Add(-time.Second)))
+						Expect(m.Verify("does not matter")).ShouldNot(HaveOccurred())
+
+						m.Populate(m.Path, attr.ChangedAfter(changed), attr.BornBefore(born))
+						actualError := m.Verify("does not matter")
+						Expect(actualError).Should(HaveOccurred())
+						Expect(actualError.(*verify.Errors).CombinedFileDifference).To(Equal(diff.ChangeTime | diff.BirthTime))
+					})
+
+					It("will report difference if birth time was expected but it isn't available", func() {
+						born = time.Time{}
+						defer func() { born = time.Unix(1400000000, 0) }()
+						m.Populate(m.Path, attr.BornBefore(time.Now()))
+						actualError := m.Verify("does not matter")
+						Expect(actualError).Should(HaveOccurred())
+						verErr := actualError.(*verify.Errors)
+						Expect(verErr.CombinedFileDifference).To(Equal(diff.BirthTime))
+						Expect(verErr.Errors[0].Err).To(MatchError("expected timestamp is not available on this platform or filesystem"))
+					})
+				})
 				It("will error if both access and modified time are different and check was required", func() {
 					m.Accessed = time.Now()
 					m.Modified = time.Now()
@@ -649,6 +956,72 @@ var _ = Describe("pkg file meta.go unit test", func() {
 					m.Populate("", attr.Gid(1001))
 					Expect(m.Gid).To(Equal(uint32(1001)))
 				})
+				It("will populate TimePrecision arbitrary value", func() {
+					m.Populate("", attr.TimePrecision(time.Second))
+					Expect(m.TimePrecision).To(Equal(time.Second))
+				})
+			})
+			Describe("with resolvers", func() {
+				It("will populate value the resolver resolves to", func() {
+					m.Populate("", attr.ResolverFunc(func() (interface{}, error) {
+						return attr.Uid(65534), nil
+					}))
+					Expect(m.Uid).To(Equal(uint32(65534)))
+					Expect(m.Validate()).ShouldNot(HaveOccurred())
+				})
+				It("will not panic when resolution fails, but report it upon Validate", func() {
+					resolutionError := errors.New("no such user")
+					m.Populate("expected/path", attr.ResolverFunc(func() (interface{}, error) {
+						return nil, resolutionError
+					}), attr.Gid(1001))
+					Expect(m.Gid).To(Equal(uint32(1001)))
+
+					err := m.Validate()
+					Expect(err).Should(HaveOccurred())
+					definitionError, ok := err.(*file.DefinitionError)
+					Expect(ok).To(BeTrue())
+					Expect(definitionError.Path).To(Equal("expected/path"))
+					Expect(definitionError.Problems).To(ConsistOf(resolutionError))
+					Expect(err.Error()).To(Equal("invalid definition of expected/path: no such user"))
+				})
+				It("will report resolver resolving to an unknown attribute type upon Validate, rather than drop it", func() {
+					resolver := attr.ResolverFunc(func() (interface{}, error) {
+						return attr.Size(3), nil
+					})
+					m.Populate("expected/path", resolver)
+					m.RejectUnknownAttributes("def.Example", []interface{}{resolver}, nil)
+
+					err := m.Validate()
+					Expect(err).Should(HaveOccurred())
+					Expect(err.Error()).To(Equal("invalid definition of expected/path: attr.ResolverFunc resolved to unknown attribute type attr.Size"))
+				})
+			})
+			Describe("with unknown attributes", func() {
+				type misspelt int64
+				It("will recognise attributes and instructions Populate handles", func() {
+					Expect(file.IsMetaAttribute(attr.Uid(3))).To(BeTrue())
+					Expect(file.IsMetaAttribute(attr.ModePerm(0700))).To(BeTrue())
+					Expect(file.IsMetaAttribute(attr.CurrentUid())).To(BeTrue())
+					Expect(file.IsMetaAttribute(attr.ResolverFunc(func() (interface{}, error) { return attr.Size(3), nil }))).To(BeFalse())
+					Expect(file.IsMetaAttribute(attr.ResolverFunc(func() (interface{}, error) { return nil, errors.New("no such user") }))).To(BeTrue())
+					Expect(file.IsMetaAttribute(verify.Size(false))).To(BeTrue())
+					Expect(file.IsMetaAttribute(attr.Size(3))).To(BeFalse())
+					Expect(file.IsMetaAttribute(misspelt(3))).To(BeFalse())
+				})
+				It("will report each attribute recognised neither by Populate nor by the given function upon Validate", func() {
+					m.Populate("expected/path")
+					m.RejectUnknownAttributes("def.Example", []interface{}{attr.Gid(4), attr.Size(3), attr.Seed(5), misspelt(6)}, func(attribute interface{}) bool {
+						_, ok := attribute.(attr.Seed)
+						return ok
+					})
+					err := m.Validate()
+					Expect(err).Should(HaveOccurred())
+					Expect(err.Error()).To(Equal("invalid definition of expected/path: def.Example: unknown attribute type attr.Size; def.Example: unknown attribute type file_test.misspelt"))
+				})
+				It("will report nothing if all attributes are recognised", func() {
+					m.RejectUnknownAttributes("def.Example", []interface{}{attr.Gid(4), os.FileMode(0700)}, nil)
+					Expect(m.Validate()).ShouldNot(HaveOccurred())
+				})
 			})
 			Describe("with verification instructions", func() {
 				isLast := func(m file.Meta, sought verify.Instruction) (valueOfVerify bool) {
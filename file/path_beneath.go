@@ -0,0 +1,93 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//returned when a definition's path would lead outside of the root it is created under
+type PathEscapeError struct {
+	Root    string
+	RelPath string
+	Reason  string
+}
+
+func (e *PathEscapeError) Error() string {
+	if e.Root == "" {
+		//found before the root is known, e.g. when a definition is populated
+		return fmt.Sprintf("%s escapes root: %s", e.RelPath, e.Reason)
+	}
+	return fmt.Sprintf("%s escapes root %s: %s", e.RelPath, e.Root, e.Reason)
+}
+
+//the lexical part of ResolveBeneath, which does not depend on root and what is under it
+func escapesLexically(relPath string) error {
+	rel := filepath.Join(".", relPath)
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return &PathEscapeError{RelPath: relPath, Reason: "path leads outside of root"}
+	}
+	return nil
+}
+
+//Will return path of relPath under root, or PathEscapeError if it leads outside of root.
+//That is either lexically (e.g. ../../etc/x) or through a symlink already present under root.
+//The final element is only checked if followFinal, i.e. when the operation on the path follows symlinks (e.g. chmod).
+//Note that the check is not atomic with the operation, it does not protect against concurrent modifications of the tree.
+//OsDisk relies on it only where openat2(2) is not available, otherwise it is merely a pre-check so that nothing
+// is touched when the path is known to escape.
+func ResolveBeneath(root, relPath string, followFinal bool) (path string, err error) {
+	path = filepath.Join(root, relPath)
+
+	rel, err := filepath.Rel(filepath.Clean(root), path)
+	if err != nil {
+		return "", &PathEscapeError{Root: root, RelPath: relPath, Reason: err.Error()}
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", &PathEscapeError{Root: root, RelPath: relPath, Reason: "path leads outside of root"}
+	}
+	if rel == "." {
+		return
+	}
+
+	realRoot, err := impl.FilepathEvalSymlinks(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			//nothing exists under root yet either
+			return path, nil
+		}
+		return "", err
+	}
+
+	elements := strings.Split(rel, string(filepath.Separator))
+	current := filepath.Clean(root)
+	for i, element := range elements {
+		current = filepath.Join(current, element)
+		info, err := impl.OsLstat(current)
+		if err != nil {
+			if os.IsNotExist(err) {
+				//the rest will be created as real directories
+				return path, nil
+			}
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 || (i == len(elements)-1 && !followFinal) {
+			continue
+		}
+		resolved, err := impl.FilepathEvalSymlinks(current)
+		if err != nil {
+			//e.g. dangling symlink, writing through it could create its target anywhere
+			return "", &PathEscapeError{Root: root, RelPath: relPath, Reason: fmt.Sprintf("symlink %s cannot be resolved: %s", current, err)}
+		}
+		if !isBeneath(realRoot, resolved) {
+			return "", &PathEscapeError{Root: root, RelPath: relPath, Reason: fmt.Sprintf("symlink %s resolves to %s", current, resolved)}
+		}
+	}
+	return
+}
+
+func isBeneath(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
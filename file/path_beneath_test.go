@@ -0,0 +1,83 @@
+package file_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"github.com/outo/filefactory/file"
+)
+
+var _ = Describe("pkg file path_beneath.go unit test", func() {
+
+	var (
+		root,
+		outside string
+	)
+
+	BeforeEach(func() {
+		file.ResetImplementation()
+		var err error
+		root, err = ioutil.TempDir("", "beneath-root-")
+		Expect(err).ShouldNot(HaveOccurred())
+		outside, err = ioutil.TempDir("", "beneath-outside-")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(root, "dir/sub"), 0777)).To(Succeed())
+		Expect(os.Symlink("dir", filepath.Join(root, "link-to-dir"))).To(Succeed())
+		Expect(os.Symlink(outside, filepath.Join(root, "link-outside"))).To(Succeed())
+		Expect(os.Symlink("../..", filepath.Join(root, "dir/link-up"))).To(Succeed())
+		Expect(os.Symlink("does-not-exist", filepath.Join(root, "dangling"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+		os.RemoveAll(outside)
+	})
+
+	DescribeTable("will resolve paths beneath root",
+		func(relPath string, followFinal bool) {
+			path, err := file.ResolveBeneath(root, relPath, followFinal)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(path).To(Equal(filepath.Join(root, relPath)))
+		},
+		Entry("not existing yet", "new/file", true),
+		Entry("existing", "dir/sub", true),
+		Entry("through symlink pointing beneath root", "link-to-dir/sub/file", true),
+		Entry("lexically leaving and re-entering", "dir/../dir/file", true),
+		Entry("final symlink pointing outside, which is not followed", "link-outside", false),
+		Entry("final dangling symlink, which is not followed", "dangling", false),
+		Entry("absolute, which is joined with root", "/dir/file", true),
+	)
+
+	DescribeTable("will refuse paths escaping root",
+		func(relPath string, followFinal bool, expectedReason string) {
+			_, err := file.ResolveBeneath(root, relPath, followFinal)
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&file.PathEscapeError{}))
+			escapeErr := err.(*file.PathEscapeError)
+			Expect(escapeErr.Root).To(Equal(root))
+			Expect(escapeErr.RelPath).To(Equal(relPath))
+			Expect(escapeErr.Reason).To(ContainSubstring(expectedReason))
+		},
+		Entry("lexically", "../../etc/x", true, "path leads outside of root"),
+		Entry("lexically, to the parent of root", "..", true, "path leads outside of root"),
+		Entry("through intermediate symlink", "link-outside/file", true, "resolves to"),
+		Entry("through final symlink which is followed", "link-outside", true, "resolves to"),
+		Entry("through relative symlink deeper in the tree", "dir/link-up/x", true, "resolves to"),
+		Entry("through dangling symlink which is followed", "dangling", true, "cannot be resolved"),
+	)
+
+	It("will not check anything under root which does not exist", func() {
+		path, err := file.ResolveBeneath(filepath.Join(root, "not-yet"), "a/b", true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(path).To(Equal(filepath.Join(root, "not-yet/a/b")))
+	})
+
+	It("will describe the escape", func() {
+		_, err := file.ResolveBeneath("/root", "../etc/x", true)
+		Expect(err).To(MatchError("../etc/x escapes root /root: path leads outside of root"))
+	})
+})
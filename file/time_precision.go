@@ -0,0 +1,53 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+//candidate timestamp granularities, from the finest to the coarsest
+var TimePrecisionCandidates = []time.Duration{
+	time.Nanosecond,
+	time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+}
+
+// Will find out timestamp granularity of the filesystem the dir is on.
+// It does so by creating a temporary file within dir, setting its times to a value with all nanoseconds digits set
+// and comparing with what has been stored.
+// Detection is opt-in: neither FileFactory nor CreateFiles invoke it (they don't know the filesystem in advance),
+// pass the result to FileFactory (or a single definition) as attr.TimePrecision, otherwise timestamps are not truncated.
+func DetectTimePrecision(dir string) (precision time.Duration, err error) {
+	probe, err := impl.IoutilTempFile(dir, ".time-precision-probe-")
+	if err != nil {
+		return
+	}
+	path := probe.Name()
+	defer impl.OsRemove(path)
+	if err = probe.Close(); err != nil {
+		return
+	}
+
+	//odd number of seconds and all nanosecond digits set, so each of the candidates truncates it differently
+	probeTime := time.Unix(1500000001, 999999999)
+	if err = impl.OsChtimes(path, probeTime, probeTime); err != nil {
+		return
+	}
+
+	meta, err := impl.WrapNewFromPath(path)
+	if err != nil {
+		return
+	}
+
+	for _, candidate := range TimePrecisionCandidates {
+		if probeTime.Truncate(candidate).Equal(meta.Modified) {
+			return candidate, nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("unable to detect time precision, set %s, stored %s", probeTime.Format(TimeLayout), meta.Modified.Format(TimeLayout)))
+}
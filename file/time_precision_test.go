@@ -0,0 +1,79 @@
+package file_test
+
+import (
+	"errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"time"
+	"github.com/outo/filefactory/file"
+)
+
+var _ = Describe("pkg file time_precision.go unit test", func() {
+
+	var tempDir string
+
+	BeforeEach(func() {
+		file.ResetImplementation()
+		var err error
+		tempDir, err = ioutil.TempDir("", "time-precision-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		file.ResetImplementation()
+		os.RemoveAll(tempDir)
+	})
+
+	It("will detect one of the candidate precisions on a real filesystem and leave no trace of the probe", func() {
+		precision, err := file.DetectTimePrecision(tempDir)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(file.TimePrecisionCandidates).To(ContainElement(precision))
+
+		entries, err := ioutil.ReadDir(tempDir)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("will detect coarse precision of the filesystem", func() {
+		file.MockForTest(func(modifyThis *file.Implementation) {
+			modifyThis.WrapNewFromPath = func(path string) (meta file.Meta, err error) {
+				meta, err = file.NewFromPath(path)
+				meta.Modified = meta.Modified.Truncate(2 * time.Second)
+				return
+			}
+		})
+		precision, err := file.DetectTimePrecision(tempDir)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(precision).To(Equal(2 * time.Second))
+	})
+
+	It("will return error if stored time cannot be explained by any of the candidates", func() {
+		file.MockForTest(func(modifyThis *file.Implementation) {
+			modifyThis.WrapNewFromPath = func(path string) (meta file.Meta, err error) {
+				meta.Modified = time.Now()
+				return
+			}
+		})
+		_, err := file.DetectTimePrecision(tempDir)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("unable to detect time precision"))
+	})
+
+	It("will return os.Chtimes error", func() {
+		expectedError := errors.New("os.Chtimes error")
+		file.MockForTest(func(modifyThis *file.Implementation) {
+			modifyThis.OsChtimes = func(name string, atime time.Time, mtime time.Time) error {
+				return expectedError
+			}
+		})
+		_, err := file.DetectTimePrecision(tempDir)
+		Expect(err).Should(MatchError(expectedError))
+	})
+
+	It("will return error if probe cannot be created", func() {
+		_, err := file.DetectTimePrecision(tempDir + "/does/not/exist")
+		Expect(err).Should(HaveOccurred())
+	})
+})
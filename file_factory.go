@@ -20,66 +20,110 @@ const MsgConstructorDidNotDoItsJob = "whoops, constructor is meant to create a w
 type FileFactory struct {
 	hardcodedFileFactoryDefaults,
 	extraFileFactoryDefaults []interface{}
+	now             time.Time
+	createOptions   CreateOptions
+	implicitParents ImplicitParents
 }
 
 type DefinitionConstructor func(hardcodedFileFactoryDefaults []interface{}, extraFileFactoryDefaults []interface{}) file.File
 
+//Factory option (pass it to New) providing the time all hardcoded default timestamps are derived from.
+//By default it is time.Now, use FixedTime to get the same timestamps on every run (e.g. for golden reports).
+type Clock func() time.Time
+
+func FixedTime(t time.Time) Clock {
+	return func() time.Time { return t }
+}
+
 func New(extraFileFactoryDefaults ...interface{}) (ff FileFactory) {
+	clock := Clock(time.Now)
+	createOptions := CreateOptions{}
+	implicitParents := ImplicitParents{}
+	attributesAndInstructions := []interface{}{}
+	for _, extra := range extraFileFactoryDefaults {
+		switch option := extra.(type) {
+		case Clock:
+			clock = option
+		case CreateOptions:
+			createOptions = option
+		case CreationPolicy:
+			createOptions.Policy = option
+		case ImplicitParents:
+			implicitParents = option
+		default:
+			attributesAndInstructions = append(attributesAndInstructions, extra)
+		}
+	}
+
+	now := clock()
+
 	//pre-pending some default values, which will be overwritten in case the variadic type already has them
 	hardcodedFileFactoryDefaults := []interface{}{
 		attr.CurrentUid(),
 		attr.PrimaryGid(),
-		attr.ModifiedTime(time.Now()),
-		attr.AccessedTime(time.Now().Add(90 * time.Minute).Add(15 * time.Second)), //added some more time as it is easier to spot than nanoseconds difference
+		attr.ModifiedTime(now),
+		attr.AccessedTime(now.Add(90 * time.Minute).Add(15 * time.Second)), //added some more time as it is easier to spot than nanoseconds difference
 	}
 
 	return FileFactory{
 		hardcodedFileFactoryDefaults: hardcodedFileFactoryDefaults,
-		extraFileFactoryDefaults:     extraFileFactoryDefaults,
+		extraFileFactoryDefaults:     attributesAndInstructions,
+		now:                          now,
+		createOptions:                createOptions,
+		implicitParents:              implicitParents,
 	}
 }
 
+//the time this factory's default timestamps were derived from, use it for timestamps of generated or snapshot definitions
+func (ff FileFactory) Now() time.Time {
+	return ff.now
+}
+
 func (ff FileFactory) FilesToCreate(constructors ...DefinitionConstructor) (files []file.File) {
 
 	for _, constructor := range constructors {
-		f := constructor(ff.hardcodedFileFactoryDefaults, ff.extraFileFactoryDefaults)
-		if f == nil {
-			panic(MsgConstructorDidNotDoItsJob)
-		}
-		files = append(files, f)
+		files = append(files, ff.construct(constructor))
+	}
+	if ff.implicitParents.Constructor != nil {
+		files = ff.withImplicitParents(files)
 	}
 	return files
 }
 
-//an alias method for FileFactory.FilesToCreate
+func (ff FileFactory) construct(constructor DefinitionConstructor) file.File {
+	f := constructor(ff.hardcodedFileFactoryDefaults, ff.extraFileFactoryDefaults)
+	if f == nil {
+		panic(MsgConstructorDidNotDoItsJob)
+	}
+	return f
+}
+
+//CreateFilesWith using CreateOptions (or CreationPolicy) this factory was created with
+func (ff FileFactory) CreateFiles(root string, files ...file.File) error {
+	return CreateFilesWith(root, ff.createOptions, files...)
+}
+
+//an alias method for FileFactory.FilesToCreate, except implicit parents are only included if ImplicitParents.Verify is set
 func (ff FileFactory) FilesToExpect(constructors ...DefinitionConstructor) (file []file.File) {
+	if !ff.implicitParents.Verify {
+		ff.implicitParents.Constructor = nil
+	}
 	return ff.FilesToCreate(constructors...)
 }
 
-//Ideally, at most one invocation per single test as per the explanation in this function.
+//Ideally, at most one invocation per single test as per the explanation in createAndAlign.
 //Otherwise, care is advised as modified timestamp may be overwritten by the system.
+//Nothing gets created if any of the definitions is invalid (see Validate).
+//Failure of any of the definitions is returned as CreationError, use CreateFilesWith to have it rolled back.
 func CreateFiles(root string, files ...file.File) (err error) {
-	for _, f := range files {
-		err = f.Create(root)
-		if err != nil {
-			return
-		}
-	}
-
-	//Split into two loops, so that attributes (especially timestamps) are aligned only once all files are created.
-	//You could have a directory created and attributes aligned, and then you may need to create a file within this directory.
-	//Doing that will update (on NIXes) modified and change timestamps on the directory itself which means the
-	// modified timestamp will be updated with current time.
-	for _, f := range files {
-		err = f.AlignAttributes(true, true, true, root)
-		if err != nil {
-			return
-		}
-	}
-	return
+	return CreateFilesWith(root, CreateOptions{}, files...)
 }
 
 func VerifyFiles(root string, expectedFiles ...file.File) (err error) {
+	if err = Validate(expectedFiles...); err != nil {
+		return
+	}
+
 	aggregatedVerificationErrors := verify.Errors{}
 	for _, f := range expectedFiles {
 		err := f.Verify(root)
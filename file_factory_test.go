@@ -19,18 +19,8 @@ import (
 
 var _ = Describe("pkg ff filefactory.go unit test", func() {
 
-	var (
-		//noError,
-		anError error
-	)
-
-	BeforeEach(func() {
-		anError = errors.New("just an error, not significant what it is")
-	})
-
-	It("will invoke user.CurrentIds() in init, to retrieve current user's id, primary group id and this user's other group id", func() {
-		//I won't test if the routine has been invoked as it is in the init function of production part (invoked before I even get to this test case)
-		//I can check that the ids have changed from zero values though.
+	It("will invoke user.CurrentIds() on first use, to retrieve current user's id, primary group id and this user's other group id", func() {
+		//I can check that the ids have changed from zero values.
 		//To do so I will use predefined attribute types which are translated to arbitrary or default values
 		actual := file.Meta{}
 		Expect(actual.Uid).To(BeZero())
@@ -52,6 +42,20 @@ var _ = Describe("pkg ff filefactory.go unit test", func() {
 		It("will create new FileFactory with default Uid and Gid (primary) set", func() {
 			//tested above
 		})
+		It("will report failure to retrieve current user's ids upon CreateFiles rather than panic", func() {
+			attr.MockForTest(func(modifyThis *attr.Implementation) {
+				modifyThis.UserCurrentIds = func() (uid uint32, primaryGid uint32, otherGid uint32, err error) {
+					err = errors.New("no passwd entry")
+					return
+				}
+			})
+			defer attr.ResetImplementation()
+
+			ff := filefactory.New()
+			files := ff.FilesToCreate(def.Dir("dir"))
+			err := filefactory.CreateFiles("/does/not/matter", files...)
+			Expect(err).To(MatchError("invalid definition of dir: unable to resolve current user's uid: no passwd entry; unable to resolve current user's primary gid: no passwd entry"))
+		})
 		It("will create new FileFactory with default Modified and Accessed times set", func() {
 			fac1 := filefactory.New()
 			files1 := fac1.FilesToCreate(def.Reg("does not matter"))
@@ -77,6 +81,29 @@ var _ = Describe("pkg ff filefactory.go unit test", func() {
 			Expect(actualAccessedTime1).To(BeTemporally("==", regular1.Accessed))
 			Expect(actualModifiedTime1).To(BeTemporally("==", regular1.Modified))
 		})
+		It("will derive default Modified and Accessed times from the Clock option, so they are the same on every run", func() {
+			base := time.Date(2017, 8, 2, 18, 19, 52, 0, time.UTC)
+			fac := filefactory.New(filefactory.FixedTime(base))
+			Expect(fac.Now()).To(BeTemporally("==", base))
+
+			files := fac.FilesToCreate(def.Reg("does not matter"), def.Dir("does not matter either"))
+			Expect(files).To(HaveLen(2))
+			for _, f := range files {
+				Expect(f.GetModified()).To(BeTemporally("==", base))
+				Expect(f.GetAccessed()).To(BeTemporally("==", base.Add(90*time.Minute+15*time.Second)))
+			}
+
+			Expect(filefactory.New(filefactory.FixedTime(base)).FilesToCreate(def.Reg("does not matter"))).To(Equal(files[:1]))
+		})
+		It("will invoke the Clock option once, on construction", func() {
+			invocations := 0
+			fac := filefactory.New(filefactory.Clock(func() time.Time {
+				invocations++
+				return time.Now()
+			}))
+			fac.FilesToCreate(def.Reg("a"), def.Reg("b"))
+			Expect(invocations).To(Equal(1))
+		})
 		It("will use the variadic parameters to overwrite common defaults for all files", func() {
 			now := time.Now()
 			expectedMode := os.FileMode(0765)
@@ -249,6 +276,17 @@ var _ = Describe("pkg ff filefactory.go unit test", func() {
 				alignAttributesInvocation{owner: true, mode: true, times: true, optionalRoot: []string{alignAttrInvocationWithRoot}},
 			))
 		})
+		It("will not create anything if any of the definitions is invalid", func() {
+			expectedValidationError := errors.New("invalid definition")
+			invalid := mock.NewFile()
+			invalid.ValidateFunc = func() error { return expectedValidationError }
+			files = append(files, invalid)
+
+			actualError := filefactory.CreateFiles(createInvocationWithRoot, files...)
+			Expect(actualError).Should(MatchError(expectedValidationError))
+			Expect(createFilesInvocations).To(BeEmpty())
+			Expect(alignAttributesInvocations).To(BeEmpty())
+		})
 		It("will return immediately if File.AlignAttributes fails", func() {
 			actualError := filefactory.CreateFiles(alignAttributesFailure, files...)
 			Expect(actualError).Should(MatchError(expectedErrorFromAlignAttributes))
@@ -320,6 +358,20 @@ var _ = Describe("pkg ff filefactory.go unit test", func() {
 			})
 		})
 
+		Context("given any of the definitions is invalid", func() {
+			expectedValidationError := errors.New("invalid definition")
+			BeforeEach(func() {
+				invalid := mock.NewFile()
+				invalid.ValidateFunc = func() error { return expectedValidationError }
+				files = append(files, invalid)
+				actualError = filefactory.VerifyFiles(verifyInvocationSuccess, files...)
+			})
+			It("will return that error without verifying anything", func() {
+				Expect(actualError).To(MatchError(expectedValidationError))
+				Expect(verifyInvocations).To(BeEmpty())
+			})
+		})
+
 		Context("given the invocation of File.Verify fails with verification error", func() {
 			BeforeEach(func() {
 				actualError = filefactory.VerifyFiles(verifyInvocationVerificationFailure, files...)
@@ -0,0 +1,50 @@
+//Package gen provides generators of pseudo-random contents of regular files (see attr.Seed and attr.Size).
+//
+//Each Generator has a versioned name, the stream of bytes it produces for a seed never changes under that name.
+//Should an algorithm need to change, it will be added under a new name, so that seed based expectations stored
+// in long-lived fixtures keep matching.
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type Generator interface {
+	//stable and versioned, e.g. "pcg32-v1"
+	Name() string
+	//endless stream of bytes, the same for the same seed
+	NewReader(seed int64) io.Reader
+}
+
+//the first size bytes of the stream for the seed, all held in memory; for large sizes read NewReader through
+// io.LimitReader instead
+func Bytes(g Generator, size, seed int64) []byte {
+	bs := make([]byte, size)
+	io.ReadFull(g.NewReader(seed), bs)
+	return bs
+}
+
+var generators = map[string]Generator{
+	Legacy.Name():  Legacy,
+	PCG32V1.Name(): PCG32V1,
+}
+
+//generator of given name, e.g. as stored in a manifest
+func ByName(name string) (Generator, error) {
+	if g, ok := generators[name]; ok {
+		return g, nil
+	}
+	return nil, errors.New(fmt.Sprintf("unknown generator %q, expected one of %v", name, Names()))
+}
+
+//names of all generators, sorted
+func Names() (names []string) {
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
@@ -0,0 +1,28 @@
+package gen_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/outo/filefactory/gen"
+)
+
+var _ = Describe("pkg gen generator.go unit test", func() {
+
+	It("will find generators by their names", func() {
+		for _, g := range []gen.Generator{gen.Legacy, gen.PCG32V1} {
+			found, err := gen.ByName(g.Name())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(found).To(Equal(g))
+		}
+	})
+
+	It("will list the names of generators when the name is unknown", func() {
+		_, err := gen.ByName("pcg32-v0")
+		Expect(err).To(MatchError(`unknown generator "pcg32-v0", expected one of [math-rand pcg32-v1]`))
+	})
+
+	It("will return as many bytes as asked for", func() {
+		Expect(gen.Bytes(gen.PCG32V1, 0, 1)).To(BeEmpty())
+		Expect(gen.Bytes(gen.Legacy, 13, 1)).To(HaveLen(13))
+	})
+})
@@ -0,0 +1,19 @@
+package gen
+
+import (
+	"io"
+	"math/rand"
+)
+
+//Generator used by default, for compatibility with definitions (and fixtures) created before generators were introduced.
+//It reads from math/rand seeded with the seed, which is deprecated and is not guaranteed to produce the same
+// stream in other implementations of Go. Prefer PCG32V1 for new definitions.
+var Legacy Generator = legacy{}
+
+type legacy struct{}
+
+func (legacy) Name() string { return "math-rand" }
+
+func (legacy) NewReader(seed int64) io.Reader {
+	return rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,21 @@
+package gen_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"math/rand"
+	"github.com/outo/filefactory/gen"
+)
+
+var _ = Describe("pkg gen legacy.go unit test", func() {
+
+	It("will produce what math/rand seeded with the seed reads", func() {
+		expected := make([]byte, 50)
+		rand.New(rand.NewSource(18)).Read(expected)
+		Expect(gen.Bytes(gen.Legacy, 50, 18)).To(Equal(expected))
+	})
+
+	It("will produce the bytes fixtures created before generators were introduced rely on", func() {
+		Expect(gen.Bytes(gen.Legacy, 8, 1)).To(Equal([]byte{0x52, 0xfd, 0xfc, 0x07, 0x21, 0x82, 0x65, 0x4f}))
+	})
+})
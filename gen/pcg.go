@@ -0,0 +1,65 @@
+package gen
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+//PCG-XSH-RR with 64 bit state and 32 bit output (pcg32 of www.pcg-random.org), version 1.
+//The state is initialised as pcg32_srandom_r does, with the seed as initstate and 54 as initseq,
+// each output is written as 4 bytes, little endian.
+//Seed 42 gives the stream of the reference pcg32-demo: 0xa15c02b7, 0x7b47f409, ...
+var PCG32V1 Generator = pcg32{}
+
+const (
+	pcg32Multiplier = 6364136223846793005
+	pcg32Sequence   = 54
+)
+
+type pcg32 struct{}
+
+func (pcg32) Name() string { return "pcg32-v1" }
+
+func (pcg32) NewReader(seed int64) io.Reader {
+	r := &pcg32Reader{increment: pcg32Sequence<<1 | 1}
+	r.next()
+	r.state += uint64(seed)
+	r.next()
+	return r
+}
+
+type pcg32Reader struct {
+	state, increment uint64
+	//bytes of the last output not read yet
+	pending    [4]byte
+	pendingLen int
+}
+
+func (r *pcg32Reader) next() uint32 {
+	old := r.state
+	r.state = old*pcg32Multiplier + r.increment
+	xorShifted := uint32(((old >> 18) ^ old) >> 27)
+	rotation := uint32(old >> 59)
+	return xorShifted>>rotation | xorShifted<<((-rotation)&31)
+}
+
+func (r *pcg32Reader) Read(p []byte) (n int, err error) {
+	for r.pendingLen > 0 && n < len(p) {
+		p[n] = r.pending[4-r.pendingLen]
+		r.pendingLen--
+		n++
+	}
+	for ; len(p)-n >= 4; n += 4 {
+		binary.LittleEndian.PutUint32(p[n:], r.next())
+	}
+	if n < len(p) {
+		binary.LittleEndian.PutUint32(r.pending[:], r.next())
+		r.pendingLen = 4
+		for n < len(p) {
+			p[n] = r.pending[4-r.pendingLen]
+			r.pendingLen--
+			n++
+		}
+	}
+	return
+}
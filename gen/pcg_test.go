@@ -0,0 +1,49 @@
+package gen_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"testing"
+	"github.com/outo/filefactory/gen"
+)
+
+var _ = Describe("pkg gen pcg.go unit test", func() {
+
+	It("will produce the stream of the reference implementation", func() {
+		bs := gen.Bytes(gen.PCG32V1, 24, 42)
+		var outputs []uint32
+		for i := 0; i < len(bs); i += 4 {
+			outputs = append(outputs, binary.LittleEndian.Uint32(bs[i:]))
+		}
+		Expect(outputs).To(Equal([]uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}))
+	})
+
+	It("will produce the same stream regardless of how it is read", func() {
+		expected := gen.Bytes(gen.PCG32V1, 103, 7)
+
+		reader := gen.PCG32V1.NewReader(7)
+		var actual []byte
+		for _, chunk := range []int{1, 2, 5, 0, 4, 3, 8, 80} {
+			bs := make([]byte, chunk)
+			_, err := io.ReadFull(reader, bs)
+			Expect(err).ShouldNot(HaveOccurred())
+			actual = append(actual, bs...)
+		}
+		Expect(actual).To(Equal(expected))
+	})
+
+	It("will produce different streams for different seeds", func() {
+		Expect(gen.Bytes(gen.PCG32V1, 16, 1)).NotTo(Equal(gen.Bytes(gen.PCG32V1, 16, 2)))
+	})
+})
+
+func BenchmarkPCG32V1(b *testing.B) {
+	const size = 1 << 20
+	b.SetBytes(size)
+	for i := 0; i < b.N; i++ {
+		io.CopyN(ioutil.Discard, gen.PCG32V1.NewReader(int64(i)), size)
+	}
+}
@@ -0,0 +1,53 @@
+package filefactory
+
+import (
+	"path/filepath"
+	"github.com/outo/filefactory/file"
+)
+
+//Factory option (pass it to New) turning parent directories which are not defined into definitions of their own.
+//Otherwise Create of the definitions makes them with umask dependent mode and current timestamps.
+//The parents are constructed with the factory defaults, so they get deterministic timestamps, and are aligned
+// in the second pass of CreateFiles as any other definition.
+//
+//	filefactory.New(filefactory.ImplicitParents{Constructor: def.Dir, Attributes: []interface{}{attr.ModePerm(0755)}})
+type ImplicitParents struct {
+	//constructor of the parents, typically def.Dir
+	Constructor func(relPath string, extraFileSpecificAttributes ...interface{}) DefinitionConstructor
+	//file specific attributes of each of the parents
+	Attributes []interface{}
+	//whether FileFactory.FilesToExpect includes the parents too, FileFactory.FilesToCreate always does
+	Verify bool
+}
+
+//inserts a definition of each of the parents which are not defined, right before the first definition needing it
+func (ff FileFactory) withImplicitParents(files []file.File) (withParents []file.File) {
+	defined := map[string]bool{}
+	for _, f := range files {
+		defined[filepath.Clean(f.GetPath())] = true
+	}
+
+	for _, f := range files {
+		path := filepath.Clean(f.GetPath())
+		if filepath.IsAbs(path) {
+			withParents = append(withParents, f)
+			continue
+		}
+
+		var missing []string
+		for parent := filepath.Dir(path); parent != "."; parent = filepath.Dir(parent) {
+			if !defined[parent] {
+				missing = append(missing, parent)
+			}
+		}
+
+		//top-most first
+		for i := len(missing) - 1; i >= 0; i-- {
+			parent := ff.implicitParents.Constructor(missing[i], ff.implicitParents.Attributes...)
+			withParents = append(withParents, ff.construct(parent))
+			defined[missing[i]] = true
+		}
+		withParents = append(withParents, f)
+	}
+	return
+}
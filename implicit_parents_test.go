@@ -0,0 +1,92 @@
+package filefactory_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/file"
+)
+
+var _ = Describe("pkg ff implicit_parents.go unit test", func() {
+
+	var (
+		root string
+		base = time.Date(2017, 8, 2, 18, 19, 52, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		filefactory.ResetImplementation()
+		var err error
+		root, err = ioutil.TempDir("", "implicit-parents-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		filefactory.ResetImplementation()
+		os.RemoveAll(root)
+	})
+
+	newFactory := func(verify bool) filefactory.FileFactory {
+		return filefactory.New(filefactory.FixedTime(base), attr.Uid(uint32(os.Getuid())), attr.Gid(uint32(os.Getgid())),
+			filefactory.ImplicitParents{Constructor: def.Dir, Attributes: []interface{}{attr.ModePerm(0750)}, Verify: verify})
+	}
+
+	paths := func(files []file.File) (paths []string) {
+		for _, f := range files {
+			paths = append(paths, f.GetPath())
+		}
+		return
+	}
+
+	It("will define each missing parent once, top-most first, right before the first definition needing it", func() {
+		files := newFactory(true).FilesToCreate(
+			def.Reg("a/b/regular"),
+			def.Dir("c"),
+			def.Sym("c/d/symlink", "../../a/b/regular"),
+			def.Reg("a/b/another"),
+		)
+		Expect(paths(files)).To(Equal([]string{"a", "a/b", "a/b/regular", "c", "c/d", "c/d/symlink", "a/b/another"}))
+		Expect(files[0]).To(BeAssignableToTypeOf(&def.Directory{}))
+	})
+
+	It("will not add anything without the option", func() {
+		files := filefactory.New().FilesToCreate(def.Reg("a/b/regular"))
+		Expect(paths(files)).To(Equal([]string{"a/b/regular"}))
+	})
+
+	It("will create the parents with the attributes given and the factory's timestamps", func() {
+		ff := newFactory(true)
+		files := ff.FilesToCreate(def.Reg("a/b/regular"))
+		Expect(filefactory.CreateFiles(root, files...)).To(Succeed())
+
+		for _, parent := range []string{"a", "a/b"} {
+			info, err := os.Lstat(filepath.Join(root, parent))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(info.IsDir()).To(BeTrue())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+			Expect(info.ModTime().Equal(base)).To(BeTrue())
+		}
+		Expect(filefactory.VerifyFiles(root, ff.FilesToExpect(def.Reg("a/b/regular"))...)).To(Succeed())
+	})
+
+	It("will include the parents in FilesToExpect only if asked to", func() {
+		Expect(paths(newFactory(true).FilesToExpect(def.Reg("a/regular")))).To(Equal([]string{"a", "a/regular"}))
+		Expect(paths(newFactory(false).FilesToExpect(def.Reg("a/regular")))).To(Equal([]string{"a/regular"}))
+	})
+
+	It("will report the parents which differ when they are verified", func() {
+		ff := newFactory(true)
+		//the child is a directory, as reading contents of a regular file would alter its accessed time
+		Expect(filefactory.CreateFiles(root, ff.FilesToCreate(def.Dir("a/dir"))...)).To(Succeed())
+		Expect(os.Chmod(filepath.Join(root, "a"), 0700)).To(Succeed())
+
+		Expect(filefactory.VerifyFiles(root, ff.FilesToExpect(def.Dir("a/dir"))...)).ShouldNot(Succeed())
+		Expect(filefactory.VerifyFiles(root, newFactory(false).FilesToExpect(def.Dir("a/dir"))...)).To(Succeed())
+	})
+})
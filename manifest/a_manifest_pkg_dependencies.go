@@ -0,0 +1,47 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"github.com/outo/filefactory/file"
+)
+
+var impl Implementation
+
+func init() {
+	ResetImplementation()
+}
+
+func GetProductionImplementation() Implementation {
+	i := Implementation{
+		//built-in
+		OsOpen:       os.Open,
+		OsCreate:     os.Create,
+		OsLstat:      os.Lstat,
+		OsReadlink:   os.Readlink,
+		FilepathWalk: filepath.Walk,
+		//custom
+		FileNewFromPath: file.NewFromPath,
+	}
+	return i
+}
+
+//not recommended to tweak in production
+func MockForTest(mocking func(modifyThis *Implementation)) {
+	mocking(&impl)
+}
+
+func ResetImplementation() {
+	impl = GetProductionImplementation()
+}
+
+type Implementation struct {
+	//builtin
+	OsOpen       func(name string) (*os.File, error)
+	OsCreate     func(name string) (*os.File, error)
+	OsLstat      func(name string) (os.FileInfo, error)
+	OsReadlink   func(name string) (string, error)
+	FilepathWalk func(root string, walkFn filepath.WalkFunc) error
+	//custom
+	FileNewFromPath func(path string) (file.Meta, error)
+}
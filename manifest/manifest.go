@@ -0,0 +1,162 @@
+//Package manifest describes file definitions as data (JSON), so that trees can be created, verified and snapshot
+// by tools other than Go code, e.g. cmd/filefactory.
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/gen"
+	"github.com/outo/filefactory/verify"
+)
+
+//values of Entry.Type
+const (
+	TypeRegular   = "reg"
+	TypeDirectory = "dir"
+	TypeSymlink   = "sym"
+)
+
+type Manifest struct {
+	Files []Entry `json:"files"`
+}
+
+//A single file definition. Apart from Type and Path (relative to root) everything is optional,
+// factory defaults apply to omitted attributes. Omitted Modified or Accessed time won't be verified.
+type Entry struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+	//symlinks only
+	Target string `json:"target,omitempty"`
+	//permissions in octal, e.g. "0750"
+	Mode string  `json:"mode,omitempty"`
+	Uid  *uint32 `json:"uid,omitempty"`
+	Gid  *uint32 `json:"gid,omitempty"`
+	Size *int64  `json:"size,omitempty"`
+	Seed *int64  `json:"seed,omitempty"`
+	//name of the generator of the contents, e.g. "pcg32-v1" (see gen.ByName), gen.Legacy if omitted
+	Generator string     `json:"generator,omitempty"`
+	Modified  *time.Time `json:"modified,omitempty"`
+	Accessed  *time.Time `json:"accessed,omitempty"`
+	//verification instructions by aspect (e.g. "contents": false), see verify.Instruction
+	Verify map[string]bool `json:"verify,omitempty"`
+}
+
+func Read(r io.Reader) (m Manifest, err error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&m)
+	return
+}
+
+func Load(path string) (m Manifest, err error) {
+	f, err := impl.OsOpen(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+func (m Manifest) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m)
+}
+
+func (m Manifest) Save(path string) (err error) {
+	f, err := impl.OsCreate(path)
+	if err != nil {
+		return
+	}
+	err = m.Write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
+
+//constructors of all entries, pass them to FileFactory.FilesToCreate or FileFactory.FilesToExpect
+func (m Manifest) Constructors() (constructors []filefactory.DefinitionConstructor, err error) {
+	for i, entry := range m.Files {
+		constructor, err := entry.Constructor()
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("entry #%d: %s", i, err))
+		}
+		constructors = append(constructors, constructor)
+	}
+	return
+}
+
+//converts this entry to one of the def constructors, as per its Type
+func (e Entry) Constructor() (constructor filefactory.DefinitionConstructor, err error) {
+	if e.Path == "" {
+		return nil, errors.New("path is required")
+	}
+
+	attributes, err := e.attributes()
+	if err != nil {
+		return
+	}
+
+	switch e.Type {
+	case TypeRegular:
+		return def.Reg(e.Path, attributes...), nil
+	case TypeDirectory:
+		return def.Dir(e.Path, attributes...), nil
+	case TypeSymlink:
+		return def.Sym(e.Path, e.Target, attributes...), nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown type %q of %s, expected one of %s, %s, %s", e.Type, e.Path, TypeRegular, TypeDirectory, TypeSymlink))
+	}
+}
+
+func (e Entry) attributes() (attributes []interface{}, err error) {
+	if e.Mode != "" {
+		mode, err := strconv.ParseUint(e.Mode, 8, 32)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid mode %q of %s: %s", e.Mode, e.Path, err))
+		}
+		attributes = append(attributes, attr.ModePerm(os.FileMode(mode)))
+	}
+	if e.Uid != nil {
+		attributes = append(attributes, attr.ArbitraryUid(*e.Uid))
+	}
+	if e.Gid != nil {
+		attributes = append(attributes, attr.ArbitraryGid(*e.Gid))
+	}
+	if e.Size != nil {
+		attributes = append(attributes, attr.Size(*e.Size))
+	}
+	if e.Seed != nil {
+		attributes = append(attributes, attr.Seed(*e.Seed))
+	}
+	if e.Generator != "" {
+		generator, err := gen.ByName(e.Generator)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid generator of %s: %s", e.Path, err))
+		}
+		attributes = append(attributes, generator)
+	}
+	if e.Modified != nil {
+		attributes = append(attributes, attr.ModifiedTime(*e.Modified))
+	} else {
+		attributes = append(attributes, verify.ModifiedTime(false))
+	}
+	if e.Accessed != nil {
+		attributes = append(attributes, attr.AccessedTime(*e.Accessed))
+	} else {
+		attributes = append(attributes, verify.AccessedTime(false))
+	}
+	for aspect, doVerify := range e.Verify {
+		attributes = append(attributes, verify.NewInstruction(doVerify, aspect))
+	}
+	return
+}
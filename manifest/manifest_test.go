@@ -0,0 +1,130 @@
+package manifest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/gen"
+	"github.com/outo/filefactory/manifest"
+	"github.com/outo/filefactory/verify"
+)
+
+var _ = Describe("pkg manifest manifest.go unit test", func() {
+
+	const document = `{
+  "files": [
+    {"type": "dir", "path": "dir", "mode": "0750", "modified": "2017-08-02T18:19:52.366534314Z"},
+    {"type": "reg", "path": "dir/file", "mode": "0640", "size": 5, "seed": 7, "uid": 0, "gid": 0, "verify": {"uid": false, "gid": false}},
+    {"type": "sym", "path": "link", "target": "dir/file"}
+  ]
+}`
+
+	It("will read manifest", func() {
+		m, err := manifest.Read(strings.NewReader(document))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.Files).To(HaveLen(3))
+		Expect(m.Files[0].Type).To(Equal(manifest.TypeDirectory))
+		Expect(*m.Files[0].Modified).To(BeTemporally("==", time.Date(2017, 8, 2, 18, 19, 52, 366534314, time.UTC)))
+		Expect(*m.Files[1].Size).To(Equal(int64(5)))
+		Expect(m.Files[1].Verify).To(Equal(map[string]bool{"uid": false, "gid": false}))
+		Expect(m.Files[2].Target).To(Equal("dir/file"))
+	})
+
+	It("will reject unknown fields, e.g. misspelt attribute", func() {
+		_, err := manifest.Read(strings.NewReader(`{"files": [{"type": "reg", "path": "f", "sise": 5}]}`))
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("will write manifest which reads back the same", func() {
+		m, err := manifest.Read(strings.NewReader(document))
+		Expect(err).ShouldNot(HaveOccurred())
+		written := &bytes.Buffer{}
+		Expect(m.Write(written)).To(Succeed())
+		readBack, err := manifest.Read(written)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(readBack).To(Equal(m))
+	})
+
+	Describe("conversion to definitions", func() {
+		var ff filefactory.FileFactory
+		BeforeEach(func() {
+			ff = filefactory.New()
+		})
+
+		It("will construct definitions of the entries' types with entries' attributes", func() {
+			m, err := manifest.Read(strings.NewReader(document))
+			Expect(err).ShouldNot(HaveOccurred())
+			constructors, err := m.Constructors()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			files := ff.FilesToCreate(constructors...)
+			Expect(files).To(HaveLen(3))
+
+			directory := files[0].(*def.Directory)
+			Expect(directory.Mode).To(Equal(os.ModeDir | 0750))
+			Expect(directory.Modified).To(BeTemporally("==", time.Date(2017, 8, 2, 18, 19, 52, 366534314, time.UTC)))
+			Expect(directory.Should(verify.ModifiedTime(true))).To(BeTrue())
+			//omitted times are not verified
+			Expect(directory.Should(verify.AccessedTime(true))).To(BeFalse())
+
+			regular := files[1].(*def.Regular)
+			Expect(regular.Path).To(Equal("dir/file"))
+			Expect(regular.Mode).To(Equal(os.FileMode(0640)))
+			Expect(regular.Size).To(Equal(int64(5)))
+			Expect(regular.Seed).To(Equal(int64(7)))
+			Expect(regular.Should(verify.Uid(true))).To(BeFalse())
+			Expect(regular.Should(verify.Gid(true))).To(BeFalse())
+
+			symlink := files[2].(*def.Symlink)
+			Expect(symlink.LinkTarget).To(Equal("dir/file"))
+		})
+
+		It("will return error for unknown type", func() {
+			_, err := manifest.Manifest{Files: []manifest.Entry{{Type: "fifo", Path: "p"}}}.Constructors()
+			Expect(err).To(MatchError(`entry #0: unknown type "fifo" of p, expected one of reg, dir, sym`))
+		})
+
+		It("will return error for invalid mode", func() {
+			_, err := manifest.Manifest{Files: []manifest.Entry{{Type: "reg", Path: "f", Mode: "rwx"}}}.Constructors()
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("will select the generator of the contents by name", func() {
+			constructors, err := manifest.Manifest{Files: []manifest.Entry{{Type: "reg", Path: "f", Generator: "pcg32-v1"}}}.Constructors()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ff.FilesToCreate(constructors...)[0].(*def.Regular).Generator).To(Equal(gen.PCG32V1))
+
+			_, err = manifest.Manifest{Files: []manifest.Entry{{Type: "reg", Path: "f", Generator: "xorshift"}}}.Constructors()
+			Expect(err).To(MatchError(`entry #0: invalid generator of f: unknown generator "xorshift", expected one of [math-rand pcg32-v1]`))
+		})
+
+		It("will return error for missing path", func() {
+			_, err := manifest.Manifest{Files: []manifest.Entry{{Type: "reg"}}}.Constructors()
+			Expect(err).To(MatchError("entry #0: path is required"))
+		})
+
+		It("will create and verify files of the manifest", func() {
+			root, err := ioutil.TempDir("", "manifest-test-")
+			Expect(err).ShouldNot(HaveOccurred())
+			defer os.RemoveAll(root)
+
+			manifestPath := filepath.Join(root, "manifest.json")
+			Expect(ioutil.WriteFile(manifestPath, []byte(document), 0600)).To(Succeed())
+			m, err := manifest.Load(manifestPath)
+			Expect(err).ShouldNot(HaveOccurred())
+			constructors, err := m.Constructors()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			tree := filepath.Join(root, "tree")
+			Expect(filefactory.CreateFiles(tree, ff.FilesToCreate(constructors...)...)).To(Succeed())
+			Expect(filefactory.VerifyFiles(tree, filefactory.New().FilesToExpect(constructors...)...)).To(Succeed())
+		})
+	})
+})
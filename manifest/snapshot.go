@@ -0,0 +1,74 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"github.com/outo/filefactory/verify"
+)
+
+//Describes existing tree under root (root itself excluded) as a manifest.
+//Contents of regular files are not captured (only their size), so contents are not verified.
+//Accessed times are not captured either, as reading the tree may change them.
+func Snapshot(root string) (m Manifest, err error) {
+	m.Files = []Entry{}
+	err = impl.FilepathWalk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entry, err := snapshotEntry(path, relPath)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, entry)
+		return nil
+	})
+	return
+}
+
+func snapshotEntry(path, relPath string) (entry Entry, err error) {
+	meta, err := impl.FileNewFromPath(path)
+	if err != nil {
+		return
+	}
+
+	uid, gid := meta.Uid, meta.Gid
+	entry = Entry{
+		Path: filepath.ToSlash(relPath),
+		Uid:  &uid,
+		Gid:  &gid,
+	}
+
+	switch {
+	case meta.Mode&os.ModeSymlink != 0:
+		entry.Type = TypeSymlink
+		entry.Target, err = impl.OsReadlink(path)
+		return
+	case meta.Mode.IsDir():
+		entry.Type = TypeDirectory
+	case meta.Mode.IsRegular():
+		entry.Type = TypeRegular
+		info, err := impl.OsLstat(path)
+		if err != nil {
+			return entry, err
+		}
+		size := info.Size()
+		entry.Size = &size
+		entry.Verify = map[string]bool{verify.Contents(false).Aspect: false}
+	default:
+		return entry, errors.New(fmt.Sprintf("unsupported type of %s (%s)", path, meta.Mode))
+	}
+
+	entry.Mode = fmt.Sprintf("%04o", meta.Mode.Perm())
+	modified := meta.Modified
+	entry.Modified = &modified
+	return
+}
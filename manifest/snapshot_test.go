@@ -0,0 +1,72 @@
+package manifest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/manifest"
+)
+
+var _ = Describe("pkg manifest snapshot.go unit test", func() {
+
+	var root string
+	modified := time.Date(2017, 8, 2, 18, 19, 52, 366534314, time.UTC)
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "snapshot-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		ff := filefactory.New(attr.ModifiedTime(modified))
+		Expect(filefactory.CreateFiles(root, ff.FilesToCreate(
+			def.Dir("dir", attr.ModePerm(0750)),
+			def.Reg("dir/file", attr.ModePerm(0640), attr.Size(33)),
+			def.Sym("link", "dir/file"),
+		)...)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	It("will describe the tree, excluding root itself", func() {
+		m, err := manifest.Snapshot(root)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(m.Files).To(HaveLen(3))
+
+		uid, gid := uint32(os.Getuid()), uint32(os.Getgid())
+		Expect(m.Files[0]).To(Equal(manifest.Entry{Type: "dir", Path: "dir", Mode: "0750", Uid: &uid, Gid: &gid, Modified: m.Files[0].Modified}))
+		Expect(*m.Files[0].Modified).To(BeTemporally("==", modified))
+
+		size := int64(33)
+		Expect(m.Files[1]).To(Equal(manifest.Entry{Type: "reg", Path: "dir/file", Mode: "0640", Uid: &uid, Gid: &gid, Size: &size, Modified: m.Files[1].Modified, Verify: map[string]bool{"contents": false}}))
+		Expect(m.Files[2]).To(Equal(manifest.Entry{Type: "sym", Path: "link", Target: "dir/file", Uid: &uid, Gid: &gid}))
+	})
+
+	It("will produce manifest the tree verifies against", func() {
+		m, err := manifest.Snapshot(root)
+		Expect(err).ShouldNot(HaveOccurred())
+		constructors, err := m.Constructors()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(filefactory.VerifyFiles(root, filefactory.New().FilesToExpect(constructors...)...)).To(Succeed())
+	})
+
+	It("will return error for unsupported file types", func() {
+		Expect(syscall.Mkfifo(filepath.Join(root, "fifo"), 0600)).To(Succeed())
+		_, err := manifest.Snapshot(root)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unsupported type of"))
+	})
+
+	It("will return error if root does not exist", func() {
+		_, err := manifest.Snapshot(filepath.Join(root, "does-not-exist"))
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,121 @@
+//matcher describes expected contents of a regular file in a looser way than Seed and Size do,
+// it is used at the time file is verified
+package match
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+)
+
+//length of actual contents' sample quoted in verification error
+const SampleLength = 50
+
+//Matcher can be passed to def.Reg alongside attributes. Once attached, contents of the regular file
+// will be verified with it instead of being compared to Seed and Size generated bytes.
+type Matcher interface {
+	//returns nil if contents match, otherwise an error describing the expectation
+	Match(contents []byte) error
+	fmt.Stringer
+}
+
+//builds a Matcher out of description and predicate, handy for writing custom matchers
+func New(description string, matches func(contents []byte) (bool, error)) Matcher {
+	return matcher{
+		description: description,
+		matches:     matches,
+	}
+}
+
+type matcher struct {
+	description string
+	matches     func(contents []byte) (bool, error)
+}
+
+func (m matcher) String() string { return m.description }
+
+func (m matcher) Match(contents []byte) error {
+	ok, err := m.matches(contents)
+	if err != nil {
+		return errors.New(fmt.Sprintf("expected contents %s, but could not match: %s", m.description, err))
+	}
+	if !ok {
+		return errors.New(fmt.Sprintf("expected contents %s, actual %s", m.description, sample(contents)))
+	}
+	return nil
+}
+
+func sample(contents []byte) string {
+	if len(contents) > SampleLength {
+		return fmt.Sprintf("%q...", contents[:SampleLength])
+	}
+	return fmt.Sprintf("%q", contents)
+}
+
+func Contains(substring string) Matcher {
+	return New(fmt.Sprintf("to contain %q", substring), func(contents []byte) (bool, error) {
+		return bytes.Contains(contents, []byte(substring)), nil
+	})
+}
+
+func Prefix(prefix string) Matcher {
+	return New(fmt.Sprintf("to start with %q", prefix), func(contents []byte) (bool, error) {
+		return bytes.HasPrefix(contents, []byte(prefix)), nil
+	})
+}
+
+func Suffix(suffix string) Matcher {
+	return New(fmt.Sprintf("to end with %q", suffix), func(contents []byte) (bool, error) {
+		return bytes.HasSuffix(contents, []byte(suffix)), nil
+	})
+}
+
+//will panic if pattern does not compile, same as regexp.MustCompile
+func Regex(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+	return New(fmt.Sprintf("to match regular expression %q", pattern), func(contents []byte) (bool, error) {
+		return re.Match(contents), nil
+	})
+}
+
+//lines are separated by '\n', last line does not need to be terminated
+func LineCount(count int) Matcher {
+	return New(fmt.Sprintf("to have %d line(s)", count), func(contents []byte) (bool, error) {
+		lines := bytes.Count(contents, []byte{'\n'})
+		if len(contents) > 0 && contents[len(contents)-1] != '\n' {
+			lines++
+		}
+		return lines == count, nil
+	})
+}
+
+//contents and expected document have to unmarshal to equal values, formatting and key order are irrelevant
+func JSONEqual(expected string) Matcher {
+	return New(fmt.Sprintf("to be JSON equivalent of %s", expected), func(contents []byte) (bool, error) {
+		return unmarshalledEqual(json.Unmarshal, []byte(expected), contents)
+	})
+}
+
+//contents and expected document have to unmarshal to equal values, formatting and key order are irrelevant
+func YAMLEqual(expected string) Matcher {
+	return New(fmt.Sprintf("to be YAML equivalent of %q", expected), func(contents []byte) (bool, error) {
+		return unmarshalledEqual(yaml.Unmarshal, []byte(expected), contents)
+	})
+}
+
+func unmarshalledEqual(unmarshal func(in []byte, out interface{}) error, expected, actual []byte) (equal bool, err error) {
+	var expectedValue, actualValue interface{}
+	if err = unmarshal(expected, &expectedValue); err != nil {
+		return false, errors.New(fmt.Sprintf("invalid expected document: %s", err))
+	}
+	if err = unmarshal(actual, &actualValue); err != nil {
+		//actual contents not being a valid document is just a mismatch
+		return false, nil
+	}
+	return reflect.DeepEqual(expectedValue, actualValue), nil
+}
@@ -0,0 +1,69 @@
+package match_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/outo/filefactory/match"
+	"strings"
+)
+
+var _ = Describe("pkg match matcher.go unit test", func() {
+
+	DescribeTable("built-in matchers will return nil if contents match",
+		func(matcher match.Matcher, contents string) {
+			Expect(matcher.Match([]byte(contents))).ShouldNot(HaveOccurred())
+		},
+		Entry("Contains", match.Contains("key"), "some key: value"),
+		Entry("Prefix", match.Prefix("#!"), "#!/bin/sh"),
+		Entry("Suffix", match.Suffix("}\n"), "{}\n"),
+		Entry("Regex", match.Regex(`port: \d+`), "host: a\nport: 8080\n"),
+		Entry("LineCount with terminated last line", match.LineCount(2), "a\nb\n"),
+		Entry("LineCount with unterminated last line", match.LineCount(2), "a\nb"),
+		Entry("LineCount of empty contents", match.LineCount(0), ""),
+		Entry("JSONEqual regardless of whitespace and key order", match.JSONEqual(`{"a": 1, "b": [true]}`), "{\n  \"b\": [ true ],\n  \"a\": 1\n}"),
+		Entry("YAMLEqual regardless of formatting and key order", match.YAMLEqual("a: 1\nb: [x, y]\n"), "b:\n  - x\n  - y\na: 1\n"),
+	)
+
+	DescribeTable("built-in matchers will return error describing the expectation if contents do not match",
+		func(matcher match.Matcher, contents string, expectedMessage string) {
+			err := matcher.Match([]byte(contents))
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(Equal(expectedMessage))
+		},
+		Entry("Contains", match.Contains("key"), "value", `expected contents to contain "key", actual "value"`),
+		Entry("Prefix", match.Prefix("#!"), "echo", `expected contents to start with "#!", actual "echo"`),
+		Entry("Suffix", match.Suffix("}"), "{", `expected contents to end with "}", actual "{"`),
+		Entry("Regex", match.Regex(`^\d+$`), "abc", `expected contents to match regular expression "^\\d+$", actual "abc"`),
+		Entry("LineCount", match.LineCount(1), "a\nb\n", `expected contents to have 1 line(s), actual "a\nb\n"`),
+		Entry("JSONEqual", match.JSONEqual(`{"a":1}`), `{"a":2}`, `expected contents to be JSON equivalent of {"a":1}, actual "{\"a\":2}"`),
+		Entry("JSONEqual with invalid actual document", match.JSONEqual(`{"a":1}`), `{"a"`, `expected contents to be JSON equivalent of {"a":1}, actual "{\"a\""`),
+		Entry("YAMLEqual", match.YAMLEqual("a: 1"), "a: 2", `expected contents to be YAML equivalent of "a: 1", actual "a: 2"`),
+	)
+
+	It("will report invalid expected document rather than a mismatch", func() {
+		err := match.JSONEqual(`{"a"`).Match([]byte(`{"a":1}`))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix(`expected contents to be JSON equivalent of {"a", but could not match: invalid expected document`))
+	})
+
+	It("will only quote a sample of long contents in the error", func() {
+		contents := strings.Repeat("x", match.SampleLength+10)
+		err := match.Contains("y").Match([]byte(contents))
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(HaveSuffix(`"` + strings.Repeat("x", match.SampleLength) + `"...`))
+	})
+
+	It("will panic on invalid regular expression, same as regexp.MustCompile", func() {
+		Expect(func() { match.Regex("(") }).To(Panic())
+	})
+
+	It("will construct custom matcher from description and predicate", func() {
+		matcher := match.New("to be empty", func(contents []byte) (bool, error) {
+			return len(contents) == 0, nil
+		})
+		Expect(matcher.String()).To(Equal("to be empty"))
+		Expect(matcher.Match(nil)).ShouldNot(HaveOccurred())
+		Expect(matcher.Match([]byte("a"))).To(MatchError(`expected contents to be empty, actual "a"`))
+	})
+})
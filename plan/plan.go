@@ -0,0 +1,191 @@
+//Package plan tells what CreateFiles would do, without touching the disk.
+//
+//Planning runs the very same filefactory.CreateFilesWith with CreateOptions.Disk recording the operations which would
+// modify the disk, rather than performing them.
+//Reading the disk (e.g. to resolve symlinks or to apply creation policies) still happens.
+package plan
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/file"
+)
+
+const (
+	OpMkdir     = "mkdir"
+	OpWrite     = "write"
+	OpSymlink   = "symlink"
+	OpChown     = "chown"
+	OpChmod     = "chmod"
+	OpChtimes   = "chtimes"
+	OpAttribute = "attribute"
+	OpRemove    = "remove"
+	OpRename    = "rename"
+	OpExchange  = "exchange"
+)
+
+//single operation modifying the disk, only fields relevant to the Op are set
+type Operation struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	//octal, e.g. 0755
+	Mode   string `json:"mode,omitempty"`
+	Size   *int64 `json:"size,omitempty"`
+	//symlink target, new path of rename or the other path of exchange
+	Target   string     `json:"target,omitempty"`
+	Uid      *uint32    `json:"uid,omitempty"`
+	Gid      *uint32    `json:"gid,omitempty"`
+	Accessed *time.Time `json:"accessed,omitempty"`
+	Modified *time.Time `json:"modified,omitempty"`
+	//aspect and value of a custom attribute
+	Aspect string      `json:"aspect,omitempty"`
+	Value  interface{} `json:"value,omitempty"`
+}
+
+func (o Operation) String() string {
+	switch o.Op {
+	case OpMkdir, OpChmod:
+		return fmt.Sprintf("%s %s %s", o.Op, o.Mode, o.Path)
+	case OpWrite:
+		return fmt.Sprintf("%s %d bytes %s %s", o.Op, *o.Size, o.Mode, o.Path)
+	case OpSymlink, OpRename:
+		return fmt.Sprintf("%s %s -> %s", o.Op, o.Path, o.Target)
+	case OpExchange:
+		return fmt.Sprintf("%s %s <-> %s", o.Op, o.Path, o.Target)
+	case OpChown:
+		return fmt.Sprintf("%s %d:%d %s", o.Op, *o.Uid, *o.Gid, o.Path)
+	case OpChtimes:
+		return fmt.Sprintf("%s accessed %s modified %s %s", o.Op, o.Accessed.Format(file.TimeLayout), o.Modified.Format(file.TimeLayout), o.Path)
+	case OpAttribute:
+		return fmt.Sprintf("%s %s=%v %s", o.Op, o.Aspect, o.Value, o.Path)
+	default:
+		return fmt.Sprintf("%s %s", o.Op, o.Path)
+	}
+}
+
+//operations in the order they would be performed
+type Plan []Operation
+
+//one operation per line
+func (p Plan) String() string {
+	lines := make([]string, 0, len(p))
+	for _, operation := range p {
+		lines = append(lines, operation.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func CreateFiles(root string, files ...file.File) (Plan, error) {
+	return CreateFilesWith(root, filefactory.CreateOptions{}, files...)
+}
+
+//Operations filefactory.CreateFilesWith would perform, in order. If it would fail, the operations up to the failure are returned with the error.
+//CreateOptions.Disk is replaced with the recorder, so the definitions have to implement file.DiskCreator and
+// file.DiskAttributesAligner (as all of pkg def do).
+//CreateOptions.Created is left untouched, as nothing is created.
+func CreateFilesWith(root string, options filefactory.CreateOptions, files ...file.File) (plan Plan, err error) {
+	r := &recorder{}
+	options.Disk = r
+	options.Created = nil
+	err = filefactory.CreateFilesWith(root, options, files...)
+	return r.plan, err
+}
+
+//file.Disk recording the operations
+type recorder struct {
+	plan Plan
+}
+
+func (r *recorder) record(operation Operation) {
+	r.plan = append(r.plan, operation)
+}
+
+func (r *recorder) MkdirAll(root, relPath string, perm os.FileMode) error {
+	r.record(Operation{Op: OpMkdir, Path: filepath.Join(root, relPath), Mode: octal(perm)})
+	return nil
+}
+
+//the contents are not read, only their size is recorded
+func (r *recorder) WriteFile(root, relPath string, contents io.Reader, size int64, perm os.FileMode) error {
+	r.record(Operation{Op: OpWrite, Path: filepath.Join(root, relPath), Mode: octal(perm), Size: &size})
+	return nil
+}
+
+func (r *recorder) Symlink(target, root, relPath string) error {
+	r.record(Operation{Op: OpSymlink, Path: filepath.Join(root, relPath), Target: target})
+	return nil
+}
+
+func (r *recorder) Lchown(root, relPath string, uid, gid int) error {
+	u, g := uint32(uid), uint32(gid)
+	r.record(Operation{Op: OpChown, Path: filepath.Join(root, relPath), Uid: &u, Gid: &g})
+	return nil
+}
+
+func (r *recorder) Chmod(root, relPath string, mode os.FileMode) error {
+	r.record(Operation{Op: OpChmod, Path: filepath.Join(root, relPath), Mode: octal(mode)})
+	return nil
+}
+
+func (r *recorder) Chtimes(root, relPath string, atime, mtime time.Time) error {
+	r.record(Operation{Op: OpChtimes, Path: filepath.Join(root, relPath), Accessed: &atime, Modified: &mtime})
+	return nil
+}
+
+func (r *recorder) CreateAttribute(handler file.AttributeHandler, path string, value interface{}) error {
+	return r.attribute(handler, path, value)
+}
+
+func (r *recorder) AlignAttribute(handler file.AttributeHandler, path string, value interface{}) error {
+	return r.attribute(handler, path, value)
+}
+
+func (r *recorder) Remove(path string) error {
+	r.record(Operation{Op: OpRemove, Path: path})
+	return nil
+}
+
+func (r *recorder) RemoveAll(path string) error {
+	return r.Remove(path)
+}
+
+func (r *recorder) Rename(oldPath, newPath string) error {
+	r.record(Operation{Op: OpRename, Path: oldPath, Target: newPath})
+	return nil
+}
+
+func (r *recorder) Exchange(path1, path2 string) error {
+	r.record(Operation{Op: OpExchange, Path: path1, Target: path2})
+	return nil
+}
+
+func (r *recorder) TempDir(dir, pattern string) (string, error) {
+	name := filepath.Join(dir, pattern+"plan")
+	r.record(Operation{Op: OpMkdir, Path: name, Mode: octal(0700)})
+	return name, nil
+}
+
+func (r *recorder) attribute(handler file.AttributeHandler, path string, value interface{}) error {
+	r.record(Operation{Op: OpAttribute, Path: path, Aspect: handler.Aspect, Value: value})
+	return nil
+}
+
+//permission bits and setuid, setgid and sticky bits the way chmod(1) takes them
+func octal(mode os.FileMode) string {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 01000
+	}
+	return fmt.Sprintf("%04o", bits)
+}
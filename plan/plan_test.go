@@ -0,0 +1,153 @@
+package plan_test
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/plan"
+	"github.com/outo/filefactory/testingaids/mock"
+)
+
+var _ = Describe("pkg plan plan.go unit test", func() {
+
+	var (
+		root  string
+		files []file.File
+		base  = time.Date(2017, 8, 2, 18, 19, 52, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "plan-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+		files = filefactory.New(filefactory.FixedTime(base), attr.Uid(uint32(os.Getuid())), attr.Gid(uint32(os.Getgid()))).FilesToCreate(
+			def.Dir("dir", attr.ModePerm(0700)),
+			def.Reg("dir/regular", attr.Size(5)),
+			def.Sym("symlink", "dir/regular"),
+		)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	expectRootEmpty := func() {
+		entries, err := ioutil.ReadDir(root)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	}
+
+	It("will list operations in the order CreateFiles performs them, attributes aligned once all files are created, without touching the disk", func() {
+		p, err := plan.CreateFiles(root, files...)
+		Expect(err).ShouldNot(HaveOccurred())
+		expectRootEmpty()
+
+		ids := fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid())
+		times := "accessed 2017-08-02 19:50:07.000000000 modified 2017-08-02 18:19:52.000000000"
+		Expect(strings.Split(p.String(), "\n")).To(Equal([]string{
+			"mkdir 0777 " + root,
+			"mkdir 0700 " + filepath.Join(root, "dir"),
+			"chmod 0700 " + filepath.Join(root, "dir"),
+			"mkdir 0777 " + filepath.Join(root, "dir"),
+			"write 5 bytes 0666 " + filepath.Join(root, "dir/regular"),
+			"chmod 0666 " + filepath.Join(root, "dir/regular"),
+			"mkdir 0777 " + root,
+			"symlink " + filepath.Join(root, "symlink") + " -> dir/regular",
+			"chown " + ids + " " + filepath.Join(root, "dir"),
+			"chmod 0700 " + filepath.Join(root, "dir"),
+			"chtimes " + times + " " + filepath.Join(root, "dir"),
+			"chown " + ids + " " + filepath.Join(root, "dir/regular"),
+			"chmod 0666 " + filepath.Join(root, "dir/regular"),
+			"chtimes " + times + " " + filepath.Join(root, "dir/regular"),
+			"chown " + ids + " " + filepath.Join(root, "symlink"),
+		}))
+	})
+
+	It("will not record planned paths as created, so that removal keeps existing directories", func() {
+		Expect(os.Mkdir(filepath.Join(root, "dir"), 0700)).To(Succeed())
+		created := &filefactory.Created{}
+
+		_, err := plan.CreateFilesWith(root, filefactory.CreateOptions{Created: created}, files...)
+		Expect(err).ShouldNot(HaveOccurred())
+		for _, f := range files {
+			Expect(created.Contains(filepath.Join(root, f.GetPath()))).To(BeFalse())
+		}
+
+		Expect(filefactory.RemoveFilesWith(root, filefactory.RemoveOptions{Created: created}, files...)).To(Succeed())
+		Expect(filepath.Join(root, "dir")).To(BeADirectory())
+	})
+
+	It("will marshal plan as JSON array of operations", func() {
+		p, err := plan.CreateFiles(root, files[1])
+		Expect(err).ShouldNot(HaveOccurred())
+
+		marshalled, err := json.Marshal(p[:2])
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(marshalled)).To(Equal(`[{"op":"mkdir","path":"` + filepath.Join(root, "dir") + `","mode":"0777"},` +
+			`{"op":"write","path":"` + filepath.Join(root, "dir/regular") + `","mode":"0666","size":5}]`))
+	})
+
+	It("will return operations up to the failure together with the error", func() {
+		Expect(os.Symlink("elsewhere", filepath.Join(root, "symlink"))).To(Succeed())
+
+		p, err := plan.CreateFilesWith(root, filefactory.CreateOptions{Policy: filefactory.FailIfExists}, files...)
+		Expect(err).To(BeAssignableToTypeOf(&filefactory.CreationError{}))
+		Expect(p).To(HaveLen(6))
+	})
+
+	It("will include removals of the creation policy and renaming of the staging directory", func() {
+		Expect(os.Mkdir(filepath.Join(root, "symlink"), 0700)).To(Succeed())
+
+		p, err := plan.CreateFilesWith(root, filefactory.CreateOptions{Policy: filefactory.Overwrite}, files...)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(p).To(ContainElement(plan.Operation{Op: plan.OpRemove, Path: filepath.Join(root, "symlink")}))
+
+		staged := filepath.Join(root, "staged")
+		p, err = plan.CreateFilesWith(staged, filefactory.CreateOptions{Staging: true}, files...)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(p[len(p)-2].Op).To(Equal(plan.OpRename))
+		Expect(p[len(p)-2].Target).To(Equal(staged))
+		Expect(p[len(p)-1].Op).To(Equal(plan.OpRemove))
+		Expect(filepath.Join(root, "symlink")).To(BeADirectory())
+	})
+
+	It("will not affect CreateFiles and VerifyFiles running at the same time", func() {
+		other, err := ioutil.TempDir("", "plan-test-other-")
+		Expect(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(other)
+
+		done := make(chan error)
+		go func() {
+			for i := 0; i < 50; i++ {
+				if _, err := plan.CreateFiles(root, files...); err != nil {
+					done <- err
+					return
+				}
+			}
+			done <- nil
+		}()
+		for i := 0; i < 50; i++ {
+			Expect(filefactory.CreateFiles(filepath.Join(other, fmt.Sprint(i)), files...)).To(Succeed())
+			Expect(filefactory.VerifyFiles(filepath.Join(other, fmt.Sprint(i)), files...)).To(Succeed())
+		}
+		Expect(<-done).To(Succeed())
+		expectRootEmpty()
+	})
+
+	It("will refuse definitions which can't be created on the recording disk", func() {
+		p, err := plan.CreateFiles(root, mock.NewFile())
+		Expect(err).To(BeAssignableToTypeOf(&filefactory.CreationError{}))
+		Expect(err.Error()).To(ContainSubstring("file.DiskCreator"))
+		Expect(p).To(BeEmpty())
+	})
+})
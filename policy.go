@@ -0,0 +1,113 @@
+package filefactory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/verify"
+)
+
+//what happens to a path which already exists when it is about to be created, see CreateOptions.Policy
+type CreationPolicy int
+
+const (
+	//each definition type behaves its own way: regular file is rewritten, directory is kept and symlink creation fails
+	LegacyCreation CreationPolicy = iota
+	//creation fails if the path exists, be it of any type
+	FailIfExists
+	//existing file is removed and created anew, existing directory is kept (if a directory is defined) and its attributes aligned.
+	//Directory where a regular file or symlink is defined is only removed if it is empty, creation fails otherwise.
+	Overwrite
+	//existing file which verifies against the definition is left in place, otherwise it is overwritten (as with Overwrite,
+	// non-empty directory where a regular file or symlink is defined fails creation)
+	SkipIfIdentical
+	//existing file of a different type than defined is removed and created anew, otherwise it is left in place (e.g. contents are not rewritten).
+	//Directory where a regular file or symlink is defined is removed along with everything in it.
+	ReplaceTypeMismatch
+)
+
+var policyNames = map[CreationPolicy]string{
+	LegacyCreation:      "LegacyCreation",
+	FailIfExists:        "FailIfExists",
+	Overwrite:           "Overwrite",
+	SkipIfIdentical:     "SkipIfIdentical",
+	ReplaceTypeMismatch: "ReplaceTypeMismatch",
+}
+
+func (p CreationPolicy) String() string {
+	if name, ok := policyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("CreationPolicy(%d)", int(p))
+}
+
+//applies the policy to the path of the definition before it gets created
+//files left in place still get their attributes aligned, as creation of their contents may have changed the timestamps
+func (c *creation) applyPolicy(f file.File) (skipCreate bool, err error) {
+	if c.options.Policy == LegacyCreation {
+		return
+	}
+
+	path, err := file.ResolveBeneath(c.root, f.GetPath(), false)
+	if err != nil {
+		//Create is going to refuse it
+		return false, nil
+	}
+
+	info, err := impl.OsLstat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return
+	}
+	sameType := info.Mode()&os.ModeType == f.GetMode()&os.ModeType
+
+	switch c.options.Policy {
+	case FailIfExists:
+		err = errors.New(fmt.Sprintf("%s already exists", path))
+		return
+	case SkipIfIdentical:
+		verificationErr := f.Verify(c.root)
+		if verificationErr == nil {
+			return true, nil
+		} else if _, isDifference := verificationErr.(*verify.Errors); !isDifference {
+			err = verificationErr
+			return
+		}
+	case ReplaceTypeMismatch:
+		if sameType {
+			return true, nil
+		}
+	case Overwrite:
+	default:
+		err = errors.New(fmt.Sprintf("unknown creation policy %s", c.options.Policy))
+		return
+	}
+
+	//overwriting
+	if sameType && info.IsDir() {
+		return
+	}
+	if info.IsDir() && c.options.Policy != ReplaceTypeMismatch {
+		return false, c.removeEmptyDir(path)
+	}
+	err = withWritableParent(c.disk, path, func() error {
+		return removeAllWritable(c.disk, path)
+	})
+	return
+}
+
+//contents of a directory are only removed recursively with ReplaceTypeMismatch
+func (c *creation) removeEmptyDir(path string) (err error) {
+	entries, err := impl.IoutilReadDir(path)
+	if err != nil {
+		return
+	}
+	if len(entries) > 0 {
+		return errors.New(fmt.Sprintf("%s is a non-empty directory, it is only replaced with %s", path, ReplaceTypeMismatch))
+	}
+	return withWritableParent(c.disk, path, func() error {
+		return c.disk.Remove(path)
+	})
+}
@@ -0,0 +1,134 @@
+package filefactory_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/diff"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/verify"
+)
+
+var _ = Describe("pkg ff policy.go unit test", func() {
+
+	var (
+		root  string
+		files []file.File
+	)
+
+	factoryDefaults := func(extra ...interface{}) []interface{} {
+		return append([]interface{}{attr.Uid(uint32(os.Getuid())), attr.Gid(uint32(os.Getgid()))}, extra...)
+	}
+
+	BeforeEach(func() {
+		filefactory.ResetImplementation()
+		var err error
+		root, err = ioutil.TempDir("", "creation-policy-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+		files = filefactory.New(factoryDefaults()...).FilesToCreate(
+			def.Dir("dir"),
+			def.Reg("dir/regular", attr.Seed(3)),
+			def.Sym("symlink", "dir/regular"),
+		)
+		Expect(filefactory.CreateFiles(root, files...)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		filefactory.ResetImplementation()
+		os.RemoveAll(root)
+	})
+
+	createWith := func(policy filefactory.CreationPolicy) error {
+		return filefactory.CreateFilesWith(root, filefactory.CreateOptions{Policy: policy}, files...)
+	}
+
+	inode := func(relPath string) uint64 {
+		info, err := os.Lstat(filepath.Join(root, relPath))
+		Expect(err).ShouldNot(HaveOccurred())
+		return info.Sys().(*syscall.Stat_t).Ino
+	}
+
+	alterRegular := func() {
+		Expect(ioutil.WriteFile(filepath.Join(root, "dir/regular"), []byte("altered"), 0666)).To(Succeed())
+	}
+
+	replaceSymlinkWithDirectory := func(withContents bool) {
+		Expect(os.Remove(filepath.Join(root, "symlink"))).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(root, "symlink"), 0700)).To(Succeed())
+		if withContents {
+			Expect(ioutil.WriteFile(filepath.Join(root, "symlink/inside"), nil, 0600)).To(Succeed())
+		}
+	}
+
+	It("will behave as before with LegacyCreation, symlink creation fails", func() {
+		err := createWith(filefactory.LegacyCreation)
+		Expect(err).To(BeAssignableToTypeOf(&filefactory.CreationError{}))
+		Expect(err.(*filefactory.CreationError).File.GetPath()).To(Equal("symlink"))
+	})
+
+	It("will fail on the first existing path with FailIfExists", func() {
+		err := createWith(filefactory.FailIfExists)
+		Expect(err).To(MatchError("create phase failed for dir: " + filepath.Join(root, "dir") + " already exists"))
+	})
+
+	It("will recreate files, keep directories and replace mismatching types with Overwrite", func() {
+		alterRegular()
+		replaceSymlinkWithDirectory(false)
+		Expect(ioutil.WriteFile(filepath.Join(root, "dir/not-defined"), nil, 0600)).To(Succeed())
+
+		Expect(createWith(filefactory.Overwrite)).To(Succeed())
+		Expect(filefactory.VerifyFiles(root, files...)).To(Succeed())
+		Expect(filepath.Join(root, "dir/not-defined")).To(BeAnExistingFile())
+	})
+
+	It("will leave identical files in place and overwrite the others with SkipIfIdentical", func() {
+		regularInode := inode("dir/regular")
+		replaceSymlinkWithDirectory(false)
+
+		Expect(createWith(filefactory.SkipIfIdentical)).To(Succeed())
+		Expect(filefactory.VerifyFiles(root, files...)).To(Succeed())
+		Expect(inode("dir/regular")).To(Equal(regularInode))
+	})
+
+	It("will refuse to remove non-empty directory where a file is defined with Overwrite and SkipIfIdentical", func() {
+		replaceSymlinkWithDirectory(true)
+
+		for _, policy := range []filefactory.CreationPolicy{filefactory.Overwrite, filefactory.SkipIfIdentical} {
+			err := createWith(policy)
+			Expect(err).To(MatchError("create phase failed for symlink: " + filepath.Join(root, "symlink") + " is a non-empty directory, it is only replaced with ReplaceTypeMismatch"))
+			Expect(filepath.Join(root, "symlink/inside")).To(BeAnExistingFile())
+		}
+	})
+
+	It("will only replace files of mismatching type with ReplaceTypeMismatch", func() {
+		alterRegular()
+		replaceSymlinkWithDirectory(true)
+
+		Expect(createWith(filefactory.ReplaceTypeMismatch)).To(Succeed())
+		err := filefactory.VerifyFiles(root, files...)
+		Expect(err).To(BeAssignableToTypeOf(&verify.Errors{}))
+		Expect(err.(*verify.Errors).CombinedFileDifference).To(Equal(diff.Size | diff.Contents))
+		Expect(err.(*verify.Errors).DifferenceFor(filepath.Join(root, "symlink"))).To(BeZero())
+	})
+
+	It("will use the policy the factory was created with", func() {
+		alterRegular()
+		fileFactory := filefactory.New(factoryDefaults(filefactory.Overwrite)...)
+		Expect(fileFactory.CreateFiles(root, files...)).To(Succeed())
+		Expect(filefactory.VerifyFiles(root, files...)).To(Succeed())
+
+		fileFactory = filefactory.New(factoryDefaults(filefactory.CreateOptions{Policy: filefactory.FailIfExists})...)
+		Expect(fileFactory.CreateFiles(root, files...)).ShouldNot(Succeed())
+	})
+
+	It("will render policy names", func() {
+		Expect(filefactory.SkipIfIdentical.String()).To(Equal("SkipIfIdentical"))
+		Expect(filefactory.CreationPolicy(42).String()).To(Equal("CreationPolicy(42)"))
+	})
+})
@@ -0,0 +1,140 @@
+package filefactory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"github.com/outo/filefactory/file"
+)
+
+//Paths of definitions CreateFilesWith created, as opposed to those it found existing, see CreateOptions.Created.
+//Pass it to RemoveFilesWith, so that only directories created by the factory get removed. Safe for concurrent use.
+type Created struct {
+	mutex sync.Mutex
+	paths map[string]bool
+}
+
+func (c *Created) add(path string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.paths == nil {
+		c.paths = map[string]bool{}
+	}
+	c.paths[filepath.Clean(path)] = true
+}
+
+//whether the definition at path (joined with root) was created
+func (c *Created) Contains(path string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.paths[filepath.Clean(path)]
+}
+
+//options of RemoveFilesWith, the zero value behaves as RemoveFiles
+type RemoveOptions struct {
+	//defined directories recorded as created are removed (if empty), others are left in place
+	Created *Created
+	//defined directories are removed if empty, even those which existed before the files were created
+	EmptyDirectories bool
+}
+
+//The inverse of CreateFiles. Will remove files described by the definitions, deepest first, so that fixtures
+// created in shared or pre-existing directories can be cleaned up.
+//Only what is defined gets removed:
+// - files which no longer exist are skipped
+// - a file of different type than defined is not removed and an error is returned
+// - directories are left in place, as they may have existed before; see RemoveFilesWith to remove those created
+//Parent directories without write permission (e.g. attr.ModePerm(0500)) are made writable for the owner just for the removal.
+func RemoveFiles(root string, files ...file.File) (err error) {
+	return RemoveFilesWith(root, RemoveOptions{}, files...)
+}
+
+//As RemoveFiles, directories are removed as the options tell.
+//A directory to be removed which still contains anything not defined (or not removed) is skipped without an error,
+// parents created implicitly and root are always left in place.
+func RemoveFilesWith(root string, options RemoveOptions, files ...file.File) (err error) {
+	if err = Validate(files...); err != nil {
+		return
+	}
+
+	ordered := append([]file.File{}, files...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return depth(ordered[i].GetPath()) > depth(ordered[j].GetPath())
+	})
+
+	for _, f := range ordered {
+		if err = removeFile(root, options, f); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func depth(relPath string) int {
+	return strings.Count(filepath.Clean(relPath), string(filepath.Separator))
+}
+
+func removeFile(root string, options RemoveOptions, f file.File) (err error) {
+	//symlinks are removed, not their targets
+	path, err := file.ResolveBeneath(root, f.GetPath(), false)
+	if err != nil {
+		return
+	}
+
+	info, err := impl.OsLstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return
+	}
+
+	if info.Mode()&os.ModeType != f.GetMode()&os.ModeType {
+		return errors.New(fmt.Sprintf("will not remove %s, it is %s but defined as %s", path, info.Mode(), f.GetMode()))
+	}
+
+	if info.IsDir() {
+		created := options.Created != nil && options.Created.Contains(path)
+		if !created && !options.EmptyDirectories {
+			return nil
+		}
+		entries, err := impl.IoutilReadDir(path)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			//contains something which is not defined
+			return nil
+		}
+	}
+
+	return withWritableParent(impl.Disk, path, func() error {
+		return impl.Disk.Remove(path)
+	})
+}
+
+//makes parent directory writable for the owner (if it is not) for the duration of remove
+func withWritableParent(disk file.Disk, path string, remove func() error) (err error) {
+	parent := filepath.Dir(path)
+	info, err := impl.OsLstat(parent)
+	if err != nil {
+		return
+	}
+
+	mode := info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	if mode&0200 != 0 {
+		return remove()
+	}
+
+	if err = disk.Chmod("", parent, mode|0200); err != nil {
+		return
+	}
+	err = remove()
+	if restoreErr := disk.Chmod("", parent, mode); err == nil {
+		err = restoreErr
+	}
+	return
+}
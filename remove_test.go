@@ -0,0 +1,187 @@
+package filefactory_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/testingaids/mock"
+)
+
+var _ = Describe("pkg ff remove.go unit test", func() {
+
+	var (
+		root        string
+		fileFactory filefactory.FileFactory
+	)
+
+	BeforeEach(func() {
+		filefactory.ResetImplementation()
+		var err error
+		root, err = ioutil.TempDir("", "remove-files-test-")
+		Expect(err).ShouldNot(HaveOccurred())
+		fileFactory = filefactory.New(attr.Uid(uint32(os.Getuid())), attr.Gid(uint32(os.Getgid())))
+	})
+
+	AfterEach(func() {
+		filefactory.ResetImplementation()
+		filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				os.Chmod(path, 0700)
+			}
+			return nil
+		})
+		os.RemoveAll(root)
+	})
+
+	exists := func(relPath string) bool {
+		_, err := os.Lstat(filepath.Join(root, relPath))
+		return err == nil
+	}
+
+	It("will remove all the files it created, including read-only directories, leaving root and implicit parents in place", func() {
+		files := fileFactory.FilesToCreate(
+			def.Dir("implicit/read-only", attr.ModePerm(0500)),
+			def.Reg("implicit/read-only/regular", attr.ModePerm(0400)),
+			def.Sym("implicit/read-only/symlink", "regular"),
+			def.Dir("implicit/read-only/nested", attr.ModePerm(0500)),
+		)
+		created := &filefactory.Created{}
+		Expect(filefactory.CreateFilesWith(root, filefactory.CreateOptions{Created: created}, files...)).To(Succeed())
+
+		Expect(filefactory.RemoveFilesWith(root, filefactory.RemoveOptions{Created: created}, files...)).To(Succeed())
+		Expect(exists("implicit/read-only")).To(BeFalse())
+		Expect(exists("implicit")).To(BeTrue())
+	})
+
+	It("will leave directories which existed before creation in place, even if empty, unless told otherwise", func() {
+		Expect(os.Mkdir(filepath.Join(root, "shared"), 0700)).To(Succeed())
+		files := fileFactory.FilesToCreate(
+			def.Dir("shared"),
+			def.Dir("shared/own"),
+			def.Reg("shared/own/regular"),
+		)
+		created := &filefactory.Created{}
+		Expect(filefactory.CreateFilesWith(root, filefactory.CreateOptions{Created: created}, files...)).To(Succeed())
+		Expect(created.Contains(filepath.Join(root, "shared"))).To(BeFalse())
+		Expect(created.Contains(filepath.Join(root, "shared/own"))).To(BeTrue())
+
+		Expect(filefactory.RemoveFilesWith(root, filefactory.RemoveOptions{Created: created}, files...)).To(Succeed())
+		Expect(exists("shared/own")).To(BeFalse())
+		Expect(exists("shared")).To(BeTrue())
+
+		Expect(filefactory.RemoveFilesWith(root, filefactory.RemoveOptions{EmptyDirectories: true}, files...)).To(Succeed())
+		Expect(exists("shared")).To(BeFalse())
+	})
+
+	It("will leave all directories in place by default, as it can't tell which of them existed before", func() {
+		files := fileFactory.FilesToCreate(
+			def.Dir("dir"),
+			def.Reg("dir/regular"),
+		)
+		Expect(filefactory.CreateFiles(root, files...)).To(Succeed())
+
+		Expect(filefactory.RemoveFiles(root, files...)).To(Succeed())
+		Expect(exists("dir/regular")).To(BeFalse())
+		Expect(exists("dir")).To(BeTrue())
+	})
+
+	It("will skip, without an error, directories with contents which are not defined", func() {
+		files := fileFactory.FilesToCreate(
+			def.Dir("dir"),
+			def.Reg("dir/regular"),
+		)
+		created := &filefactory.Created{}
+		Expect(filefactory.CreateFilesWith(root, filefactory.CreateOptions{Created: created}, files...)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(root, "dir/not-defined"), nil, 0600)).To(Succeed())
+
+		Expect(filefactory.RemoveFilesWith(root, filefactory.RemoveOptions{Created: created}, files...)).To(Succeed())
+		Expect(exists("dir/regular")).To(BeFalse())
+		Expect(exists("dir/not-defined")).To(BeTrue())
+	})
+
+	It("will record paths under root rather than the staging directory", func() {
+		files := fileFactory.FilesToCreate(def.Dir("dir"))
+		created := &filefactory.Created{}
+		staged := filepath.Join(root, "staged")
+		Expect(filefactory.CreateFilesWith(staged, filefactory.CreateOptions{Staging: true, Created: created}, files...)).To(Succeed())
+
+		Expect(filefactory.RemoveFilesWith(staged, filefactory.RemoveOptions{Created: created}, files...)).To(Succeed())
+		Expect(exists("staged/dir")).To(BeFalse())
+	})
+
+	It("will remove symlink rather than its target", func() {
+		files := fileFactory.FilesToCreate(def.Sym("symlink", "target"))
+		Expect(filefactory.CreateFiles(root, files...)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(root, "target"), nil, 0600)).To(Succeed())
+
+		Expect(filefactory.RemoveFiles(root, files...)).To(Succeed())
+		Expect(exists("symlink")).To(BeFalse())
+		Expect(exists("target")).To(BeTrue())
+	})
+
+	It("will skip files which do not exist", func() {
+		Expect(filefactory.RemoveFiles(root, fileFactory.FilesToCreate(def.Reg("missing/regular"), def.Dir("missing"))...)).To(Succeed())
+	})
+
+	It("will not remove a file of different type than defined", func() {
+		Expect(os.Mkdir(filepath.Join(root, "actually-directory"), 0700)).To(Succeed())
+
+		err := filefactory.RemoveFiles(root, fileFactory.FilesToCreate(def.Reg("actually-directory"))...)
+		Expect(err).To(MatchError(ContainSubstring("will not remove " + filepath.Join(root, "actually-directory"))))
+		Expect(exists("actually-directory")).To(BeTrue())
+	})
+
+	It("will not remove anything if any of the definitions is invalid", func() {
+		files := fileFactory.FilesToCreate(def.Reg("regular"))
+		Expect(filefactory.CreateFiles(root, files...)).To(Succeed())
+
+		err := filefactory.RemoveFiles(root, append(files, fileFactory.FilesToCreate(def.Reg("../escape"))...)...)
+		Expect(err).Should(HaveOccurred())
+		Expect(exists("regular")).To(BeTrue())
+	})
+
+	Describe("given parent directory is not writable", func() {
+		var (
+			chmods []os.FileMode
+			disk   *mock.Disk
+		)
+
+		BeforeEach(func() {
+			chmods = nil
+			Expect(os.Mkdir(filepath.Join(root, "read-only"), 0500)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(root, "read-only/regular"), nil, 0600)).To(Succeed())
+			disk = mock.NewDisk()
+			disk.ChmodFunc = func(diskRoot, relPath string, mode os.FileMode) error {
+				Expect(filepath.Join(diskRoot, relPath)).To(Equal(filepath.Join(root, "read-only")))
+				chmods = append(chmods, mode)
+				return os.Chmod(filepath.Join(diskRoot, relPath), mode)
+			}
+			disk.RemoveFunc = os.Remove
+			filefactory.MockForTest(func(modifyThis *filefactory.Implementation) {
+				modifyThis.Disk = disk
+			})
+		})
+
+		It("will make it writable for the duration of removal and restore its mode", func() {
+			files := []file.File{def.Reg("read-only/regular")(nil, nil)}
+			Expect(filefactory.RemoveFiles(root, files...)).To(Succeed())
+			Expect(chmods).To(Equal([]os.FileMode{0700, 0500}))
+			Expect(exists("read-only/regular")).To(BeFalse())
+		})
+
+		It("will restore its mode even if removal fails", func() {
+			expectedError := errors.New("os.Remove error")
+			disk.RemoveFunc = func(path string) error { return expectedError }
+			files := []file.File{def.Reg("read-only/regular")(nil, nil)}
+			Expect(filefactory.RemoveFiles(root, files...)).To(MatchError(expectedError))
+			Expect(chmods).To(Equal([]os.FileMode{0700, 0500}))
+		})
+	})
+})
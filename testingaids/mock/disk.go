@@ -0,0 +1,73 @@
+package mock
+
+import (
+	"io"
+	"os"
+	"time"
+	"github.com/outo/filefactory/file"
+)
+
+//file.Disk with each of the operations replaceable, by default they do nothing and succeed
+type Disk struct {
+	MkdirAllFunc        func(root, relPath string, perm os.FileMode) error
+	WriteFileFunc       func(root, relPath string, contents io.Reader, size int64, perm os.FileMode) error
+	SymlinkFunc         func(target, root, relPath string) error
+	LchownFunc          func(root, relPath string, uid, gid int) error
+	ChmodFunc           func(root, relPath string, mode os.FileMode) error
+	ChtimesFunc         func(root, relPath string, atime, mtime time.Time) error
+	CreateAttributeFunc func(handler file.AttributeHandler, path string, value interface{}) error
+	AlignAttributeFunc  func(handler file.AttributeHandler, path string, value interface{}) error
+	RemoveFunc          func(path string) error
+	RemoveAllFunc       func(path string) error
+	RenameFunc          func(oldPath, newPath string) error
+	ExchangeFunc        func(path1, path2 string) error
+	TempDirFunc         func(dir, pattern string) (string, error)
+}
+
+func NewDisk() *Disk {
+	return &Disk{
+		MkdirAllFunc:        func(root, relPath string, perm os.FileMode) error { return nil },
+		WriteFileFunc:       func(root, relPath string, contents io.Reader, size int64, perm os.FileMode) error { return nil },
+		SymlinkFunc:         func(target, root, relPath string) error { return nil },
+		LchownFunc:          func(root, relPath string, uid, gid int) error { return nil },
+		ChmodFunc:           func(root, relPath string, mode os.FileMode) error { return nil },
+		ChtimesFunc:         func(root, relPath string, atime, mtime time.Time) error { return nil },
+		CreateAttributeFunc: func(handler file.AttributeHandler, path string, value interface{}) error { return nil },
+		AlignAttributeFunc:  func(handler file.AttributeHandler, path string, value interface{}) error { return nil },
+		RemoveFunc:          func(path string) error { return nil },
+		RemoveAllFunc:       func(path string) error { return nil },
+		RenameFunc:          func(oldPath, newPath string) error { return nil },
+		ExchangeFunc:        func(path1, path2 string) error { return nil },
+		TempDirFunc:         func(dir, pattern string) (string, error) { return dir, nil },
+	}
+}
+
+func (m *Disk) MkdirAll(root, relPath string, perm os.FileMode) error {
+	return m.MkdirAllFunc(root, relPath, perm)
+}
+func (m *Disk) WriteFile(root, relPath string, contents io.Reader, size int64, perm os.FileMode) error {
+	return m.WriteFileFunc(root, relPath, contents, size, perm)
+}
+func (m *Disk) Symlink(target, root, relPath string) error {
+	return m.SymlinkFunc(target, root, relPath)
+}
+func (m *Disk) Lchown(root, relPath string, uid, gid int) error {
+	return m.LchownFunc(root, relPath, uid, gid)
+}
+func (m *Disk) Chmod(root, relPath string, mode os.FileMode) error {
+	return m.ChmodFunc(root, relPath, mode)
+}
+func (m *Disk) Chtimes(root, relPath string, atime, mtime time.Time) error {
+	return m.ChtimesFunc(root, relPath, atime, mtime)
+}
+func (m *Disk) CreateAttribute(handler file.AttributeHandler, path string, value interface{}) error {
+	return m.CreateAttributeFunc(handler, path, value)
+}
+func (m *Disk) AlignAttribute(handler file.AttributeHandler, path string, value interface{}) error {
+	return m.AlignAttributeFunc(handler, path, value)
+}
+func (m *Disk) Remove(path string) error                    { return m.RemoveFunc(path) }
+func (m *Disk) RemoveAll(path string) error                 { return m.RemoveAllFunc(path) }
+func (m *Disk) Rename(oldPath, newPath string) error        { return m.RenameFunc(oldPath, newPath) }
+func (m *Disk) Exchange(path1, path2 string) error          { return m.ExchangeFunc(path1, path2) }
+func (m *Disk) TempDir(dir, pattern string) (string, error) { return m.TempDirFunc(dir, pattern) }
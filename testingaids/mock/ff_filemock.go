@@ -1,14 +1,19 @@
 package mock
 
 import (
+	"fmt"
 	"time"
 	"os"
 )
 
+//every new mock gets a distinct path, so that mocks are not reported as duplicate definitions
+var mocksCreated int
+
 type File struct {
 	CreateFunc          func(root string) error
 	AlignAttributesFunc func(ownershipInAnyCase, modeIfApplicable, timesIfApplicable bool, optionalRoot ...string) (err error)
 	VerifyFunc          func(root string) error
+	ValidateFunc        func() error
 	StringFunc          func() string
 	GetPathFunc         func() string
 	GetModeFunc         func() os.FileMode
@@ -19,10 +24,15 @@ type File struct {
 }
 
 func NewFile() File {
+	mocksCreated++
+	path := fmt.Sprintf("mock/file%d", mocksCreated)
 	return File{
 		CreateFunc:          func(root string) error { return nil },
 		StringFunc:          func() string { return "String() result" },
 		VerifyFunc:          func(root string) error { return nil },
+		ValidateFunc:        func() error { return nil },
+		GetPathFunc:         func() string { return path },
+		GetModeFunc:         func() os.FileMode { return 0 },
 		AlignAttributesFunc: func(ownershipInAnyCase, modeIfApplicable, timesIfApplicable bool, optionalRoot ...string) (err error) { return nil },
 	}
 }
@@ -30,6 +40,7 @@ func NewFile() File {
 func (m File) Create(root string) error                                                    { return m.CreateFunc(root) }
 func (m File) AlignAttributes(owner, mode, times bool, optionalRoot ...string) (err error) { return m.AlignAttributesFunc(owner, mode, times, optionalRoot...) }
 func (m File) Verify(root string) error                                                    { return m.VerifyFunc(root) }
+func (m File) Validate() error                                                              { return m.ValidateFunc() }
 func (m File) String() string                                                              { return m.StringFunc() }
 func (m File) GetPath() string                                                             { return m.GetPathFunc() }
 func (m File) GetMode() os.FileMode                                                        { return m.GetModeFunc() }
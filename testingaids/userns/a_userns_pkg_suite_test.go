@@ -0,0 +1,13 @@
+package userns_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestUserns(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Userns pkg Suite")
+}
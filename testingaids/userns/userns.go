@@ -0,0 +1,29 @@
+//Package userns helps covering scenarios which normally require a superuser (e.g. attr.ArbitraryUid) without sudo.
+//Test re-executes its own binary inside an unprivileged user namespace, where the current user is mapped to root
+// and can chown files to mapped ids: 0 within the namespace, and the subordinate ids of the user (see Run) as 1 and
+// above, Mapped tells how many there are.
+//
+//	It("needs root", func() {
+//		if !userns.Inside() {
+//			if reason := userns.Unavailable(); reason != "" {
+//				Skip(reason)
+//			}
+//			output, err := userns.Run(CurrentGinkgoTestDescription().FullTestText)
+//			Expect(err).ShouldNot(HaveOccurred(), string(output))
+//			return
+//		}
+//		//the actual test, running as root of the user namespace
+//	})
+package userns
+
+import (
+	"os"
+)
+
+//set in the environment of the re-executed test binary
+const EnvMarker = "FILEFACTORY_IN_USERNS"
+
+//will return true if this process has been started with Command
+func Inside() bool {
+	return os.Getenv(EnvMarker) == "1"
+}
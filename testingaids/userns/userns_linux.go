@@ -0,0 +1,218 @@
+// +build linux
+
+package userns
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/user"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+var (
+	availability      sync.Once
+	unavailableReason string
+)
+
+//set (along with EnvMarker) when the parent writes the mappings with newuidmap and newgidmap after the start
+const envAwaitMappings = "FILEFACTORY_USERNS_AWAIT_MAPPINGS"
+
+//the child can't do anything requiring ids before the mappings are written, the parent closes the pipe once they are
+func init() {
+	if Inside() && os.Getenv(envAwaitMappings) == "1" {
+		mapped := os.NewFile(3, "mappings written")
+		ioutil.ReadAll(mapped)
+		mapped.Close()
+	}
+}
+
+//will return command re-executing current binary (os.Args[0]) with given arguments in a new user namespace.
+//Current uid and gid are mapped to 0 within the namespace. If the process is privileged (e.g. root in a container),
+// the subordinate ids of the current user (/etc/subuid and /etc/subgid) are mapped to 1 and above too, root without
+// them gets 0 to 65535 mapped to the same ids. Otherwise see Run, the only ids mapped by the command are 0.
+func Command(args ...string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), EnvMarker+"=1")
+	if os.Geteuid() == 0 {
+		cmd.SysProcAttr = sysProcAttr(idMappings(os.Getuid(), "/etc/subuid", true), idMappings(os.Getgid(), "/etc/subgid", true))
+	} else {
+		cmd.SysProcAttr = sysProcAttr(idMappings(os.Getuid(), "", false), idMappings(os.Getgid(), "", false))
+	}
+	return cmd
+}
+
+//will re-execute current (ginkgo) test binary in a user namespace, focused on the spec with given full text.
+//Unprivileged user gets subordinate ids mapped by newuidmap(1) and newgidmap(1), if they are installed and
+// /etc/subuid and /etc/subgid list the user, otherwise the namespace is the one of Command.
+func Run(fullSpecText string) (combinedOutput []byte, err error) {
+	args := []string{"-ginkgo.focus=" + regexp.QuoteMeta(fullSpecText)}
+	if os.Geteuid() != 0 {
+		uids, gids := idMappings(os.Getuid(), "/etc/subuid", false), idMappings(os.Getgid(), "/etc/subgid", false)
+		if len(uids) > 1 && len(gids) > 1 && helpersInstalled() {
+			return runMappedByHelpers(args, uids, gids)
+		}
+	}
+	return Command(args...).CombinedOutput()
+}
+
+//Number of ids (uids and gids alike) mapped within the namespace, from 0 up, files can be chowned to these.
+//Zero outside of the namespace.
+func Mapped() uint32 {
+	if !Inside() {
+		return 0
+	}
+	uids, gids := mappedFromZero("/proc/self/uid_map"), mappedFromZero("/proc/self/gid_map")
+	if uids < gids {
+		return uids
+	}
+	return gids
+}
+
+func sysProcAttr(uids, gids []syscall.SysProcIDMap) *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER,
+		UidMappings: uids,
+		GidMappings: gids,
+		//unprivileged user can't map gids unless setgroups is denied
+		GidMappingsEnableSetgroups: false,
+	}
+}
+
+//id maps to 0, subordinate ids (if listed in the file) to 1 and above
+func idMappings(id int, subordinateFile string, privileged bool) []syscall.SysProcIDMap {
+	mappings := []syscall.SysProcIDMap{{ContainerID: 0, HostID: id, Size: 1}}
+	if start, count, ok := subordinateIds(subordinateFile); ok {
+		mappings = append(mappings, syscall.SysProcIDMap{ContainerID: 1, HostID: start, Size: count})
+	} else if privileged && id == 0 {
+		mappings = append(mappings, syscall.SysProcIDMap{ContainerID: 1, HostID: 1, Size: 65535})
+	}
+	return mappings
+}
+
+//the first range of the current user (by name or uid) in /etc/subuid or /etc/subgid format
+func subordinateIds(subordinateFile string) (start, count int, ok bool) {
+	if subordinateFile == "" {
+		return
+	}
+	f, err := os.Open(subordinateFile)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	owners := map[string]bool{strconv.Itoa(os.Getuid()): true}
+	if current, err := user.Current(); err == nil {
+		owners[current.Username] = true
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ":")
+		if len(fields) != 3 || !owners[fields[0]] {
+			continue
+		}
+		start, startErr := strconv.Atoi(fields[1])
+		count, countErr := strconv.Atoi(fields[2])
+		if startErr == nil && countErr == nil && count > 0 {
+			return start, count, true
+		}
+	}
+	return
+}
+
+func helpersInstalled() bool {
+	for _, helper := range []string{"newuidmap", "newgidmap"} {
+		if _, err := exec.LookPath(helper); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+//starts the command without mappings, writes them with the setuid helpers and lets the command continue
+func runMappedByHelpers(args []string, uids, gids []syscall.SysProcIDMap) (combinedOutput []byte, err error) {
+	mapped, mappingsWritten, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	var output bytes.Buffer
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), EnvMarker+"=1", envAwaitMappings+"=1")
+	cmd.ExtraFiles = []*os.File{mapped}
+	cmd.Stdout, cmd.Stderr = &output, &output
+	cmd.SysProcAttr = &syscall.SysProcAttr{Cloneflags: syscall.CLONE_NEWUSER}
+	err = cmd.Start()
+	mapped.Close()
+	if err != nil {
+		mappingsWritten.Close()
+		return
+	}
+
+	err = writeMappings(cmd.Process.Pid, uids, gids)
+	if err != nil {
+		cmd.Process.Kill()
+	}
+	mappingsWritten.Close()
+	if waitErr := cmd.Wait(); err == nil {
+		err = waitErr
+	}
+	return output.Bytes(), err
+}
+
+func writeMappings(pid int, uids, gids []syscall.SysProcIDMap) error {
+	for helper, mappings := range map[string][]syscall.SysProcIDMap{"newuidmap": uids, "newgidmap": gids} {
+		args := []string{strconv.Itoa(pid)}
+		for _, mapping := range mappings {
+			args = append(args, strconv.Itoa(mapping.ContainerID), strconv.Itoa(mapping.HostID), strconv.Itoa(mapping.Size))
+		}
+		if output, err := exec.Command(helper, args...).CombinedOutput(); err != nil {
+			return errors.New(fmt.Sprintf("%s failed: %s: %s", helper, err, strings.TrimSpace(string(output))))
+		}
+	}
+	return nil
+}
+
+//number of ids mapped contiguously from 0, as listed in /proc/self/uid_map or gid_map
+func mappedFromZero(mapFile string) (count uint32) {
+	contents, err := ioutil.ReadFile(mapFile)
+	if err != nil {
+		return
+	}
+	ranges := map[uint32]uint32{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		inside, insideErr := strconv.ParseUint(fields[0], 10, 32)
+		size, sizeErr := strconv.ParseUint(fields[2], 10, 32)
+		if insideErr == nil && sizeErr == nil {
+			ranges[uint32(inside)] = uint32(size)
+		}
+	}
+	for size, ok := ranges[count]; ok && size > 0; size, ok = ranges[count] {
+		count += size
+	}
+	return
+}
+
+//will return the reason user namespaces can't be used (e.g. disabled by kernel.unprivileged_userns_clone or seccomp),
+// or empty string if they can
+func Unavailable() (reason string) {
+	availability.Do(func() {
+		cmd := exec.Command("/bin/true")
+		cmd.SysProcAttr = sysProcAttr(idMappings(os.Getuid(), "", false), idMappings(os.Getgid(), "", false))
+		if err := cmd.Run(); err != nil {
+			unavailableReason = fmt.Sprintf("user namespaces are not available: %s", err)
+		}
+	})
+	return unavailableReason
+}
@@ -0,0 +1,44 @@
+package userns_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/outo/filefactory/testingaids/userns"
+	"os"
+)
+
+var _ = Describe("pkg userns unit test", func() {
+
+	It("will build command re-executing current binary with given arguments and environment marker", func() {
+		cmd := userns.Command("-ginkgo.focus=something")
+		Expect(cmd.Path).To(Equal(os.Args[0]))
+		Expect(cmd.Args[1:]).To(Equal([]string{"-ginkgo.focus=something"}))
+		Expect(cmd.Env).To(ContainElement(userns.EnvMarker + "=1"))
+	})
+
+	It("will report whether this process runs inside user namespace created by Command", func() {
+		Expect(userns.Inside()).To(Equal(os.Getenv(userns.EnvMarker) == "1"))
+	})
+
+	It("will run focused spec as root of the user namespace", func() {
+		if !userns.Inside() {
+			if reason := userns.Unavailable(); reason != "" {
+				Skip(reason)
+			}
+			output, err := userns.Run(CurrentGinkgoTestDescription().FullTestText)
+			Expect(err).ShouldNot(HaveOccurred(), string(output))
+			Expect(string(output)).To(ContainSubstring("1 Passed"))
+			return
+		}
+		Expect(os.Getuid()).To(BeZero())
+		Expect(os.Getgid()).To(BeZero())
+		Expect(userns.Mapped()).To(BeNumerically(">=", 1))
+	})
+
+	It("will report no mapped ids outside of the user namespace", func() {
+		if userns.Inside() {
+			Skip("runs outside of the user namespace only")
+		}
+		Expect(userns.Mapped()).To(BeZero())
+	})
+})
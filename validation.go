@@ -0,0 +1,97 @@
+package filefactory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"github.com/outo/filefactory/file"
+)
+
+//all invalid definitions found by Validate, each is *file.DefinitionError unless a custom Validator returned something else
+type DefinitionErrors []error
+
+func (e DefinitionErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+//each of the invalid definitions, so that errors.Is and errors.As can be used on DefinitionErrors
+func (e DefinitionErrors) Unwrap() []error {
+	return e
+}
+
+//Will check definitions before anything is created or verified. Reported are problems of individual definitions
+// (e.g. unknown attribute types, unresolvable attributes) as well as problems between them:
+// empty and absolute paths, paths which are root itself or lead outside of it, paths defined more than once and paths nested under a definition which is neither a directory nor a symlink.
+//Returns DefinitionErrors or nil.
+func Validate(files ...file.File) error {
+	//index of the first definition of each path
+	definitions := map[string]int{}
+	for i, f := range files {
+		path := filepath.Clean(f.GetPath())
+		if _, defined := definitions[path]; !defined {
+			definitions[path] = i
+		}
+	}
+
+	var invalid DefinitionErrors
+	reported := map[string]bool{}
+	for i, f := range files {
+		path := filepath.Clean(f.GetPath())
+		var problems []error
+
+		var ownError error
+		if validator, ok := f.(file.Validator); ok {
+			ownError = validator.Validate()
+		}
+		var definitionError *file.DefinitionError
+		if errors.As(ownError, &definitionError) {
+			problems = append(problems, definitionError.Problems...)
+		} else if ownError != nil {
+			problems = append(problems, ownError)
+		}
+
+		if f.GetPath() == "" {
+			problems = append(problems, errors.New("path is empty"))
+		} else if filepath.IsAbs(path) {
+			problems = append(problems, errors.New("path has to be relative to root"))
+		} else if path == "." || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+			problems = append(problems, errors.New("path has to be beneath root"))
+		}
+
+		if definitions[path] != i && !reported[path] {
+			reported[path] = true
+			problems = append(problems, errors.New("path is defined more than once"))
+		}
+
+		for parent := filepath.Dir(path); parent != "." && parent != string(filepath.Separator); parent = filepath.Dir(parent) {
+			if index, defined := definitions[parent]; defined && files[index].GetMode()&(os.ModeDir|os.ModeSymlink) == 0 {
+				problems = append(problems, errors.New(fmt.Sprintf("parent %s is defined as %s, not a directory", parent, files[index].GetMode())))
+				break
+			}
+		}
+
+		if len(problems) == 0 {
+			continue
+		}
+		if len(problems) == 1 && ownError != nil && definitionError == nil {
+			//custom Validator's error is passed as is
+			invalid = append(invalid, ownError)
+			continue
+		}
+		invalid = append(invalid, &file.DefinitionError{
+			Path:     f.GetPath(),
+			Problems: problems,
+		})
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+	return invalid
+}
@@ -0,0 +1,105 @@
+package filefactory_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"errors"
+	"github.com/outo/filefactory"
+	"github.com/outo/filefactory/testingaids/mock"
+	"github.com/outo/filefactory/file"
+	"github.com/outo/filefactory/attr"
+	"github.com/outo/filefactory/def"
+)
+
+var _ = Describe("pkg ff validation.go unit test", func() {
+
+	construct := func(constructors ...filefactory.DefinitionConstructor) (files []file.File) {
+		for _, constructor := range constructors {
+			files = append(files, constructor(nil, nil))
+		}
+		return
+	}
+
+	It("will return nil if all definitions are valid", func() {
+		Expect(filefactory.Validate(construct(
+			def.Dir("dir"),
+			def.Reg("dir/regular"),
+			def.Sym("symlink", "dir"),
+			def.Reg("symlink/regular"),
+		)...)).ShouldNot(HaveOccurred())
+	})
+
+	It("will report problems of individual definitions", func() {
+		err := filefactory.Validate(construct(
+			def.Dir("dir", attr.Size(3)),
+			def.Reg("dir/regular"),
+		)...)
+		Expect(err).To(MatchError("invalid definition of dir: def.Dir: unknown attribute type attr.Size"))
+	})
+
+	It("will report absolute paths", func() {
+		err := filefactory.Validate(construct(def.Reg("/absolute"))...)
+		Expect(err).To(MatchError("invalid definition of /absolute: path has to be relative to root"))
+	})
+
+	It("will report empty paths and paths which are not beneath root", func() {
+		err := filefactory.Validate(construct(def.Reg(""))...)
+		Expect(err).To(MatchError("invalid definition of : path is empty"))
+
+		for _, path := range []string{".", "a/..", "..", "../x", "a/../../x"} {
+			err = filefactory.Validate(construct(def.Reg(path))...)
+			Expect(err).To(MatchError("invalid definition of " + path + ": path has to be beneath root"))
+		}
+
+		Expect(filefactory.Validate(construct(def.Reg("a/../x"), def.Reg("..x"))...)).ShouldNot(HaveOccurred())
+	})
+
+	It("will report path defined more than once, only once", func() {
+		err := filefactory.Validate(construct(
+			def.Reg("a/regular"),
+			def.Dir("a/./regular"),
+			def.Reg("a/regular/"),
+		)...)
+		Expect(err).To(MatchError("invalid definition of a/./regular: path is defined more than once"))
+	})
+
+	It("will report definitions nested under a definition which is neither a directory nor a symlink", func() {
+		err := filefactory.Validate(construct(
+			def.Reg("regular"),
+			def.Dir("regular/dir"),
+			def.Reg("regular/dir/nested"),
+		)...)
+		Expect(err).To(MatchError("invalid definition of regular/dir: parent regular is defined as -rw-rw-rw-, not a directory\n" +
+			"invalid definition of regular/dir/nested: parent regular is defined as -rw-rw-rw-, not a directory"))
+	})
+
+	It("will combine problems of a definition and allow errors.As on each invalid definition", func() {
+		err := filefactory.Validate(construct(
+			def.Reg("regular"),
+			def.Reg("regular", attr.Size(1), "misspelt"),
+		)...)
+		Expect(err).To(BeAssignableToTypeOf(filefactory.DefinitionErrors{}))
+		Expect(err.(filefactory.DefinitionErrors)).To(HaveLen(1))
+
+		var definitionError *file.DefinitionError
+		Expect(errors.As(err, &definitionError)).To(BeTrue())
+		Expect(definitionError.Path).To(Equal("regular"))
+		Expect(definitionError.Problems).To(HaveLen(2))
+		Expect(err.Error()).To(Equal("invalid definition of regular: def.Reg: unknown attribute type string; path is defined more than once"))
+	})
+
+	It("will pass error of a custom Validator as is, unless there are other problems with that definition", func() {
+		expectedError := errors.New("custom validation error")
+		custom := mock.NewFile()
+		custom.ValidateFunc = func() error { return expectedError }
+
+		err := filefactory.Validate(custom)
+		Expect(errors.Is(err, expectedError)).To(BeTrue())
+		Expect(err.(filefactory.DefinitionErrors)[0]).To(Equal(expectedError))
+
+		custom.GetPathFunc = func() string { return "/absolute" }
+		err = filefactory.Validate(custom)
+		Expect(errors.Is(err, expectedError)).To(BeTrue())
+		Expect(err).To(MatchError("invalid definition of /absolute: custom validation error; path has to be relative to root"))
+	})
+})
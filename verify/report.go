@@ -0,0 +1,108 @@
+package verify
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"github.com/outo/filefactory/diff"
+)
+
+//single verification error in a form suitable for machine consumption (e.g. CI dashboards)
+type ReportEntry struct {
+	Path       string      `json:"path"`
+	RelPath    string      `json:"relPath,omitempty"`
+	Difference string      `json:"difference"`
+	Expected   interface{} `json:"expected,omitempty"`
+	Actual     interface{} `json:"actual,omitempty"`
+	//first differing byte, set for diff.Contents only (so that offset 0 is reported too)
+	Offset     *int64      `json:"offset,omitempty"`
+	Message    string      `json:"message"`
+}
+
+type Report struct {
+	Difference string        `json:"difference"`
+	Errors     []ReportEntry `json:"errors"`
+}
+
+func (ves *Errors) Report() Report {
+	report := Report{
+		Difference: ves.CombinedFileDifference.String(),
+		Errors:     []ReportEntry{},
+	}
+	for _, verr := range ves.Errors {
+		entry := ReportEntry{
+			Path:       verr.Path,
+			RelPath:    verr.RelPath,
+			Difference: verr.FileDifference.String(),
+			Expected:   verr.Expected,
+			Actual:     verr.Actual,
+		}
+		if verr.FileDifference&diff.Contents != 0 {
+			offset := verr.Offset
+			entry.Offset = &offset
+		}
+		if verr.Err != nil {
+			entry.Message = verr.Err.Error()
+		}
+		report.Errors = append(report.Errors, entry)
+	}
+	return report
+}
+
+//encodes these errors as JSON report, see Report
+func (ves *Errors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ves.Report())
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+//encodes these errors as JUnit XML test suite of given name, with a failing test case per path
+func (ves *Errors) JUnit(suiteName string) ([]byte, error) {
+	byPath := map[string]*junitTestCase{}
+	paths := []string{}
+	for _, entry := range ves.Report().Errors {
+		testCase, ok := byPath[entry.Path]
+		if !ok {
+			testCase = &junitTestCase{Name: entry.Path, ClassName: suiteName}
+			byPath[entry.Path] = testCase
+			paths = append(paths, entry.Path)
+		}
+		testCase.Failures = append(testCase.Failures, junitFailure{
+			Type:    entry.Difference,
+			Message: entry.Message,
+			Text:    fmt.Sprintf("expected: %v\nactual: %v", entry.Expected, entry.Actual),
+		})
+	}
+	sort.Strings(paths)
+
+	suite := junitTestSuite{Name: suiteName, Tests: len(paths)}
+	for _, path := range paths {
+		suite.Failures += 1
+		suite.TestCases = append(suite.TestCases, *byPath[path])
+	}
+
+	encoded, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), encoded...), nil
+}
@@ -0,0 +1,106 @@
+package verify_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+	"github.com/outo/filefactory/diff"
+	"github.com/outo/filefactory/verify"
+)
+
+var _ = Describe("pkg verify report.go unit test", func() {
+
+	var verErr verify.Errors
+	modified := time.Date(2017, 8, 2, 18, 19, 52, 366534314, time.UTC)
+
+	BeforeEach(func() {
+		verErr = verify.Errors{}
+		verErr.AddError(verify.NewDifference(diff.ModePerm, "/root/dir/file", "dir/file", os.FileMode(0750), os.FileMode(0700), errors.New("expected -rwxr-x---, actual -rwx------")))
+		verErr.AddError(verify.NewDifference(diff.Owner, "/root/dir/file", "dir/file", uint32(501), uint32(0), errors.New("expected 501, actual 0")))
+		verErr.AddError(verify.NewDifference(diff.ModTime, "/root/other", "other", modified, modified.Add(time.Second), errors.New("expected ..., actual ...")))
+		verErr.Add(diff.NotPresentOrNotAccessible, "/root/missing", errors.New("file does not exist"))
+	})
+
+	It("will report each error with its path, name of the difference and typed expected and actual values", func() {
+		report := verErr.Report()
+		Expect(report.Difference).To(Equal("NotPresentOrNotAccessible|ModePerm|Owner|ModTime"))
+		Expect(report.Errors).To(HaveLen(4))
+		Expect(report.Errors[0]).To(Equal(verify.ReportEntry{
+			Path:       "/root/dir/file",
+			RelPath:    "dir/file",
+			Difference: "ModePerm",
+			Expected:   os.FileMode(0750),
+			Actual:     os.FileMode(0700),
+			Message:    "expected -rwxr-x---, actual -rwx------",
+		}))
+		Expect(report.Errors[3].Expected).To(BeNil())
+	})
+
+	It("will encode report as JSON", func() {
+		encoded, err := json.Marshal(&verErr)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		decoded := map[string]interface{}{}
+		Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+		Expect(decoded["difference"]).To(Equal("NotPresentOrNotAccessible|ModePerm|Owner|ModTime"))
+		entries := decoded["errors"].([]interface{})
+		Expect(entries).To(HaveLen(4))
+		Expect(entries[1]).To(Equal(map[string]interface{}{
+			"path":       "/root/dir/file",
+			"relPath":    "dir/file",
+			"difference": "Owner",
+			"expected":   float64(501),
+			"actual":     float64(0),
+			"message":    "expected 501, actual 0",
+		}))
+		Expect(entries[2].(map[string]interface{})["expected"]).To(Equal("2017-08-02T18:19:52.366534314Z"))
+		Expect(entries[3]).ToNot(HaveKey("expected"))
+	})
+
+	It("will report offset of contents difference even if it is the first byte, and of no other difference", func() {
+		verErr = verify.Errors{}
+		contents := verify.NewDifference(diff.Contents, "/root/a", "a", nil, nil, errors.New("differs at 0"))
+		verErr.AddError(contents)
+		verErr.AddError(verify.NewDifference(diff.Owner, "/root/a", "a", uint32(501), uint32(0), errors.New("expected 501, actual 0")))
+
+		report := verErr.Report()
+		Expect(report.Errors[0].Offset).ToNot(BeNil())
+		Expect(*report.Errors[0].Offset).To(BeZero())
+		Expect(report.Errors[1].Offset).To(BeNil())
+
+		encoded, err := json.Marshal(&verErr)
+		Expect(err).ShouldNot(HaveOccurred())
+		decoded := map[string]interface{}{}
+		Expect(json.Unmarshal(encoded, &decoded)).To(Succeed())
+		entries := decoded["errors"].([]interface{})
+		Expect(entries[0]).To(HaveKeyWithValue("offset", float64(0)))
+		Expect(entries[1]).ToNot(HaveKey("offset"))
+	})
+
+	It("will name unknown difference bits by their value", func() {
+		verErr = verify.Errors{}
+		verErr.Add(diff.FileDifference(1<<40), "/root/x", errors.New("custom"))
+		Expect(verErr.Report().Difference).To(Equal("0x10000000000"))
+	})
+
+	It("will encode report as JUnit XML, with a failing test case per path", func() {
+		encoded, err := verErr.JUnit("fixtures")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(encoded)).To(Equal(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="fixtures" tests="3" failures="3">
+  <testcase name="/root/dir/file" classname="fixtures">
+    <failure type="ModePerm" message="expected -rwxr-x---, actual -rwx------">expected: -rwxr-x---&#xA;actual: -rwx------</failure>
+    <failure type="Owner" message="expected 501, actual 0">expected: 501&#xA;actual: 0</failure>
+  </testcase>
+  <testcase name="/root/missing" classname="fixtures">
+    <failure type="NotPresentOrNotAccessible" message="file does not exist">expected: &lt;nil&gt;&#xA;actual: &lt;nil&gt;</failure>
+  </testcase>
+  <testcase name="/root/other" classname="fixtures">
+    <failure type="ModTime" message="expected ..., actual ...">expected: 2017-08-02 18:19:52.366534314 +0000 UTC&#xA;actual: 2017-08-02 18:19:53.366534314 +0000 UTC</failure>
+  </testcase>
+</testsuite>`))
+	})
+})
@@ -0,0 +1,137 @@
+package verify
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"github.com/outo/filefactory/diff"
+)
+
+//compact markers rendered next to the path in Tree, add your own for the differences allocated with diff.Register
+// (their names are rendered otherwise)
+var Markers = map[diff.FileDifference]string{
+	diff.All:                       "*",
+	diff.NotPresentOrNotAccessible: "!",
+	diff.ModeType:                  "T",
+	diff.ModePerm:                  "P",
+	diff.Owner:                     "U",
+	diff.Group:                     "G",
+	diff.ModTime:                   "M",
+	diff.AccTime:                   "A",
+	diff.Size:                      "S",
+	diff.LinkTarget:                "L",
+	diff.Contents:                  "C",
+	diff.ChangeTime:                "c",
+	diff.BirthTime:                 "B",
+}
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiFaint = "\x1b[2m"
+	ansiReset = "\x1b[0m"
+)
+
+type treeNode struct {
+	children map[string]*treeNode
+	errors   []Error
+}
+
+func (n *treeNode) child(name string) *treeNode {
+	if n.children == nil {
+		n.children = map[string]*treeNode{}
+	}
+	if _, ok := n.children[name]; !ok {
+		n.children[name] = &treeNode{}
+	}
+	return n.children[name]
+}
+
+//Renders the errors grouped by path (relative one if available) as an indented directory tree.
+//Each path with errors is followed by markers of its differences (see Markers) and the error messages.
+//Colour will highlight them with ANSI escape codes.
+//
+//	relative/
+//	  path/
+//	    directory [P A]
+//	      ModePerm: expected drwx------, actual drwxr-xr-x
+//	      AccTime: expected ..., actual ...
+func (ves *Errors) Tree(colour bool) string {
+	root := &treeNode{}
+	for _, verr := range ves.Errors {
+		path := verr.RelPath
+		if path == "" {
+			path = verr.Path
+		}
+		elements := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+		if len(elements) > 1 && elements[0] == "" {
+			//absolute path
+			elements = append([]string{"/" + elements[1]}, elements[2:]...)
+		}
+		node := root
+		for _, element := range elements {
+			node = node.child(element)
+		}
+		node.errors = append(node.errors, verr)
+	}
+
+	rendered := &strings.Builder{}
+	root.render(rendered, 0, colour)
+	return rendered.String()
+}
+
+func (n *treeNode) render(rendered *strings.Builder, depth int, colour bool) {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	indent := strings.Repeat("  ", depth)
+	for _, name := range names {
+		node := n.children[name]
+		rendered.WriteString(indent)
+		if len(node.errors) > 0 {
+			rendered.WriteString(highlight(colour, ansiRed, name))
+		} else {
+			rendered.WriteString(name)
+		}
+		if len(node.children) > 0 {
+			rendered.WriteString("/")
+		}
+		if len(node.errors) > 0 {
+			rendered.WriteString(" " + highlight(colour, ansiRed, "["+node.markers()+"]"))
+		}
+		rendered.WriteString("\n")
+		for _, verr := range node.errors {
+			message := verr.FileDifference.String()
+			if verr.Err != nil {
+				message += ": " + verr.Err.Error()
+			}
+			rendered.WriteString(indent + "    " + highlight(colour, ansiFaint, message) + "\n")
+		}
+		node.render(rendered, depth+1, colour)
+	}
+}
+
+func (n *treeNode) markers() string {
+	var combined diff.FileDifference
+	for _, verr := range n.errors {
+		combined |= verr.FileDifference
+	}
+	markers := []string{}
+	for _, bit := range combined.Bits() {
+		if marker, ok := Markers[bit]; ok {
+			markers = append(markers, marker)
+		} else {
+			markers = append(markers, bit.String())
+		}
+	}
+	return strings.Join(markers, " ")
+}
+
+func highlight(colour bool, code, text string) string {
+	if !colour {
+		return text
+	}
+	return code + text + ansiReset
+}
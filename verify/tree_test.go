@@ -0,0 +1,55 @@
+package verify_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"errors"
+	"github.com/outo/filefactory/diff"
+	"github.com/outo/filefactory/verify"
+)
+
+var _ = Describe("pkg verify tree.go unit test", func() {
+
+	var verErr verify.Errors
+
+	BeforeEach(func() {
+		verErr = verify.Errors{}
+		verErr.AddError(verify.NewDifference(diff.ModePerm, "/root/a/b/file", "a/b/file", nil, nil, errors.New("expected -rwxr-x---, actual -rwx------")))
+		verErr.AddError(verify.NewDifference(diff.AccTime, "/root/a", "a", nil, nil, errors.New("expected x, actual y")))
+		verErr.AddError(verify.NewDifference(diff.Owner, "/root/a/b/file", "a/b/file", nil, nil, errors.New("expected 501, actual 0")))
+		verErr.AddError(verify.NewDifference(diff.NotPresentOrNotAccessible, "/root/a/another", "a/another", nil, nil, errors.New("file does not exist")))
+		verErr.Add(diff.LinkTarget, "/elsewhere/link", errors.New("expected t, actual u"))
+	})
+
+	It("will render errors grouped by path as an indented tree, with markers per difference", func() {
+		Expect(verErr.Tree(false)).To(Equal(`/elsewhere/
+  link [L]
+      LinkTarget: expected t, actual u
+a/ [A]
+    AccTime: expected x, actual y
+  another [!]
+      NotPresentOrNotAccessible: file does not exist
+  b/
+    file [P U]
+        ModePerm: expected -rwxr-x---, actual -rwx------
+        Owner: expected 501, actual 0
+`))
+	})
+
+	It("will highlight paths with errors, markers and messages with ANSI colour if requested", func() {
+		verErr = verify.Errors{}
+		verErr.AddError(verify.NewDifference(diff.Size, "/root/f", "f", nil, nil, errors.New("expected 1, actual 2")))
+		Expect(verErr.Tree(true)).To(Equal("\x1b[31mf\x1b[0m \x1b[31m[S]\x1b[0m\n    \x1b[2mSize: expected 1, actual 2\x1b[0m\n"))
+	})
+
+	It("will render name of a difference without marker", func() {
+		custom := diff.Register("TreeTestCustom")
+		verErr = verify.Errors{}
+		verErr.AddError(verify.NewDifference(custom|diff.Size, "/root/f", "f", nil, nil, errors.New("custom")))
+		Expect(verErr.Tree(false)).To(HavePrefix("f [S TreeTestCustom]\n"))
+	})
+
+	It("will render nothing if there are no errors", func() {
+		Expect((&verify.Errors{}).Tree(false)).To(BeEmpty())
+	})
+})
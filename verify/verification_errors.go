@@ -8,7 +8,36 @@ import (
 type Error struct {
 	diff.FileDifference
 	Path string
-	Err  error
+	//path as it appears in the definition (relative to root)
+	RelPath string
+	//typed values of the differing aspect (e.g. os.FileMode, uint32, time.Time), nil if not applicable
+	Expected,
+	Actual interface{}
+	//first differing byte of the contents, only meaningful for diff.Contents
+	Offset int64
+	Err    error
+}
+
+//like os.PathError, the message is prefixed with the path
+func (e Error) Error() string {
+	if e.Err == nil {
+		return e.Path
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+//Allows errors.Is(err, verify.Error{FileDifference: diff.Owner}) to find out if there is an Owner difference (for any path).
+//Path of the target, if not empty, has to match as well.
+func (e Error) Is(target error) bool {
+	t, ok := target.(Error)
+	if !ok {
+		return false
+	}
+	return t.FileDifference&e.FileDifference != 0 && (t.Path == "" || t.Path == e.Path)
 }
 
 func NewErr(difference diff.FileDifference, path string, err error) Error {
@@ -19,6 +48,18 @@ func NewErr(difference diff.FileDifference, path string, err error) Error {
 	}
 }
 
+//error carrying relative path as well as expected and actual values of the differing aspect
+func NewDifference(difference diff.FileDifference, path, relPath string, expected, actual interface{}, err error) Error {
+	return Error{
+		FileDifference: difference,
+		Path:           path,
+		RelPath:        relPath,
+		Expected:       expected,
+		Actual:         actual,
+		Err:            err,
+	}
+}
+
 type Errors struct {
 	error
 	CombinedFileDifference diff.FileDifference
@@ -26,12 +67,12 @@ type Errors struct {
 }
 
 func (ves *Errors) Add(fileDifference diff.FileDifference, path string, err error) {
-	ves.CombinedFileDifference |= fileDifference
-	ves.Errors = append(ves.Errors, Error{
-		FileDifference: fileDifference,
-		Path:           path,
-		Err:            err,
-	})
+	ves.AddError(NewErr(fileDifference, path, err))
+}
+
+func (ves *Errors) AddError(verr Error) {
+	ves.CombinedFileDifference |= verr.FileDifference
+	ves.Errors = append(ves.Errors, verr)
 }
 
 func (ves *Errors) Merge(err error) (nonVerificationError error) {
@@ -40,13 +81,22 @@ func (ves *Errors) Merge(err error) (nonVerificationError error) {
 			nonVerificationError = err
 		} else {
 			for _, verr := range verrs.Errors {
-				ves.Add(verr.FileDifference, verr.Path, verr.Err)
+				ves.AddError(verr)
 			}
 		}
 	}
 	return
 }
 
+//each of the verification errors, so that errors.Is and errors.As can be used on Errors
+func (ves *Errors) Unwrap() []error {
+	errs := make([]error, 0, len(ves.Errors))
+	for _, verr := range ves.Errors {
+		errs = append(errs, verr)
+	}
+	return errs
+}
+
 func (ves *Errors) IsFileNotPresentOrNotAccessible() bool {
 	return ves.CombinedFileDifference&diff.NotPresentOrNotAccessible != 0
 }
@@ -65,7 +115,7 @@ func (ves *Errors) MapToNilIfNone() error {
 func (ves *Errors) Error() string {
 	collated := ""
 	for _, err := range ves.Errors {
-		collated += fmt.Sprintf("%s\n", err.Err.Error())
+		collated += fmt.Sprintf("%s\n", err.Error())
 	}
 	return collated
 }
@@ -4,6 +4,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"errors"
+	"os"
 	"github.com/outo/filefactory/diff"
 	"github.com/outo/filefactory/verify"
 )
@@ -47,6 +48,51 @@ var _ = Describe("pkg ff verification_errors.go unit test", func() {
 		))
 	})
 
+	It("will add an error carrying relative path, expected and actual values", func() {
+		verr := verify.Errors{}
+		verr.AddError(verify.NewDifference(fileDifferenceOf4, "/root/some path", "some path", uint32(1), uint32(2), anError))
+		Expect(verr.CombinedFileDifference).To(Equal(fileDifferenceOf4))
+		Expect(verr.Errors).To(ConsistOf(
+			verify.Error{FileDifference: fileDifferenceOf4, Path: "/root/some path", RelPath: "some path", Expected: uint32(1), Actual: uint32(2), Err: anError},
+		))
+
+		merged := verify.Errors{}
+		Expect(merged.Merge(&verr)).ShouldNot(HaveOccurred())
+		Expect(merged.Errors).To(Equal(verr.Errors))
+	})
+
+	Describe("standard library error handling", func() {
+		var verErr *verify.Errors
+		BeforeEach(func() {
+			verErr = &verify.Errors{}
+			verErr.AddError(verify.NewDifference(diff.Owner, "/root/some path", "some path", uint32(501), uint32(0), anError))
+			verErr.AddError(verify.NewDifference(diff.ModePerm, "/root/other path", "other path", os.FileMode(0750), os.FileMode(0700), anotherError))
+		})
+		It("will describe single error with its path", func() {
+			Expect(verErr.Errors[0].Error()).To(Equal("/root/some path: " + anError.Error()))
+		})
+		It("will unwrap single error to the original error", func() {
+			Expect(errors.Unwrap(verErr.Errors[0])).To(Equal(anError))
+		})
+		It("will let errors.Is find original errors and differences within Errors", func() {
+			var err error = verErr
+			Expect(errors.Is(err, anError)).To(BeTrue())
+			Expect(errors.Is(err, anotherError)).To(BeTrue())
+			Expect(errors.Is(err, someError)).To(BeFalse())
+			Expect(errors.Is(err, verify.Error{FileDifference: diff.Owner})).To(BeTrue())
+			Expect(errors.Is(err, verify.Error{FileDifference: diff.Owner, Path: "/root/some path"})).To(BeTrue())
+			Expect(errors.Is(err, verify.Error{FileDifference: diff.Owner, Path: "/root/other path"})).To(BeFalse())
+			Expect(errors.Is(err, verify.Error{FileDifference: diff.Group})).To(BeFalse())
+		})
+		It("will let errors.As retrieve typed expected and actual values", func() {
+			var err error = verErr
+			var verr verify.Error
+			Expect(errors.As(err, &verr)).To(BeTrue())
+			Expect(verr.Expected).To(Equal(uint32(501)))
+			Expect(verr.Actual).To(Equal(uint32(0)))
+		})
+	})
+
 	Describe("given sample of 2 verification errors inside verErr", func() {
 		var verErr verify.Errors
 		BeforeEach(func() {
@@ -58,6 +104,9 @@ var _ = Describe("pkg ff verification_errors.go unit test", func() {
 			Expect(verErr.Error()).To(ContainSubstring(anError.Error()))
 			Expect(verErr.Error()).To(ContainSubstring(anotherError.Error()))
 		})
+		It("will prefix each of the error messages with its path", func() {
+			Expect(verErr.Error()).To(Equal("some path (8): " + anError.Error() + "\nsome path (4): " + anotherError.Error() + "\n"))
+		})
 
 		Describe("merging errors", func() {
 			It("will merge provided error into this, if of type Errors, then will return nil", func() {
@@ -21,3 +21,5 @@ func Gid(verify bool) Instruction           { return NewInstruction(verify, "gid
 func Size(verify bool) Instruction          { return NewInstruction(verify, "size") }
 func SymlinkTarget(verify bool) Instruction { return NewInstruction(verify, "symlink-target") }
 func Contents(verify bool) Instruction      { return NewInstruction(verify, "contents") }
+func ChangedTime(verify bool) Instruction   { return NewInstruction(verify, "changed") }
+func BirthTime(verify bool) Instruction     { return NewInstruction(verify, "birth") }
@@ -40,6 +40,8 @@ var _ = Describe("fs/copy.go unit test", func() {
 		Entry("Size", verify.Size, true, "size"),
 		Entry("SymlinkTarget", verify.SymlinkTarget, true, "symlink-target"),
 		Entry("Contents", verify.Contents, true, "contents"),
+		Entry("ChangedTime", verify.ChangedTime, true, "changed"),
+		Entry("BirthTime", verify.BirthTime, true, "birth"),
 		Entry("AllByDefault", verify.AllByDefault, false, "all"),
 		Entry("ModePerm", verify.ModePerm, false, "mode-perm"),
 		Entry("ModifiedTime", verify.ModifiedTime, false, "modified"),
@@ -49,5 +51,7 @@ var _ = Describe("fs/copy.go unit test", func() {
 		Entry("Size", verify.Size, false, "size"),
 		Entry("SymlinkTarget", verify.SymlinkTarget, false, "symlink-target"),
 		Entry("Contents", verify.Contents, false, "contents"),
+		Entry("ChangedTime", verify.ChangedTime, false, "changed"),
+		Entry("BirthTime", verify.BirthTime, false, "birth"),
 	)
 })